@@ -0,0 +1,75 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/cucumber/godog"
+	flagd "github.com/open-feature/go-sdk-contrib/providers/flagd/pkg"
+	"github.com/open-feature/go-sdk-contrib/tests/flagd/pkg/integration"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestFlagdInRPC(t *testing.T) {
+	if testing.Short() {
+		// skip e2e if testing -short
+		t.Skip()
+	}
+
+	flag.Parse()
+
+	name := "flagd.feature"
+
+	testSuite := godog.TestSuite{
+		Name: name,
+		TestSuiteInitializer: integration.InitializeFlagdTestSuite(func() openfeature.FeatureProvider {
+			return flagd.NewProvider(flagd.WithPort(8013))
+		}),
+		ScenarioInitializer: integration.InitializeFlagdScenario,
+		Options: &godog.Options{
+			Format: "pretty",
+			Paths: []string{
+				"../flagd-testbed/gherkin/flagd.feature",
+				"../flagd-testbed/gherkin/flagd-rpc-caching.feature",
+			},
+			TestingT: t, // Testing instance that will run subtests.
+			Strict:   true,
+		},
+	}
+
+	if testSuite.Run() != 0 {
+		t.Fatal("non-zero status returned, failed to run flagd tests")
+	}
+}
+
+func TestFlagdInProcess(t *testing.T) {
+	if testing.Short() {
+		// skip e2e if testing -short
+		t.Skip()
+	}
+
+	flag.Parse()
+
+	name := "flagd.feature"
+
+	testSuite := godog.TestSuite{
+		Name: name,
+		TestSuiteInitializer: integration.InitializeFlagdTestSuite(func() openfeature.FeatureProvider {
+			return flagd.NewProvider(flagd.WithInProcessResolver(), flagd.WithPort(9090))
+		}, integration.WithReadyTimeout(5*time.Second)),
+		ScenarioInitializer: integration.InitializeFlagdScenario,
+		Options: &godog.Options{
+			Format:   "pretty",
+			Paths:    []string{"../flagd-testbed/gherkin/flagd.feature"},
+			TestingT: t, // Testing instance that will run subtests.
+			Strict:   true,
+		},
+	}
+
+	if testSuite.Run() != 0 {
+		t.Fatal("non-zero status returned, failed to run flagd tests")
+	}
+}
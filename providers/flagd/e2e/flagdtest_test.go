@@ -0,0 +1,122 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"flag"
+	"fmt"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/cucumber/godog"
+	flagd "github.com/open-feature/go-sdk-contrib/providers/flagd/pkg"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/pkg/flagdtest"
+	"github.com/open-feature/go-sdk-contrib/tests/flagd/pkg/integration"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// testbedFlags are the flags of the flagd testbed used by the features, with the variant of the changing flags
+func testbedFlags(variant string) string {
+	return fmt.Sprintf(`{"flags": {
+	"boolean-flag": {"state": "ENABLED", "variants": {"on": true, "off": false}, "defaultVariant": "on"},
+	"integer-flag": {"state": "ENABLED", "variants": {"one": 1, "ten": 10}, "defaultVariant": "ten"},
+	"float-flag": {"state": "ENABLED", "variants": {"tenth": 0.1, "half": 0.5}, "defaultVariant": "half"},
+	"object-flag": {"state": "ENABLED", "variants": {"empty": {}, "template": {"showImages": true, "title": "Check out these pics!", "imagesPerPage": 100}}, "defaultVariant": "template"},
+	"boolean-zero-flag": {"state": "ENABLED", "variants": {"zero": false}, "defaultVariant": "zero"},
+	"string-zero-flag": {"state": "ENABLED", "variants": {"zero": ""}, "defaultVariant": "zero"},
+	"integer-zero-flag": {"state": "ENABLED", "variants": {"zero": 0}, "defaultVariant": "zero"},
+	"float-zero-flag": {"state": "ENABLED", "variants": {"zero": 0.0}, "defaultVariant": "zero"},
+	"change-flag": {"state": "ENABLED", "variants": {"foo": "foo", "bar": "bar"}, "defaultVariant": %q},
+	"changing-flag": {"state": "ENABLED", "variants": {"foo": "foo", "bar": "bar"}, "defaultVariant": %q}
+}}`, variant, variant)
+}
+
+// runWithFake runs the features against an in-process fake of flagd, which modifies flags and drops connections on
+// demand of the features instead of periodically, hence no flagd testbed container is required
+func runWithFake(t *testing.T, paths []string, providerSupplier func(*flagdtest.Server) openfeature.FeatureProvider) {
+	if testing.Short() {
+		// skip e2e if testing -short
+		t.Skip()
+	}
+
+	flag.Parse()
+
+	variant := "foo"
+	server, err := flagdtest.New(testbedFlags(variant))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	testSuite := godog.TestSuite{
+		Name: "flagdtest",
+		TestSuiteInitializer: integration.InitializeFlagdTestSuite(func() openfeature.FeatureProvider {
+			return providerSupplier(server)
+		},
+			integration.WithReadyTimeout(2*time.Second),
+			integration.WithEventTimeout(5*time.Second),
+			integration.WithFlagModifier(func(string) error {
+				if variant == "foo" {
+					variant = "bar"
+				} else {
+					variant = "foo"
+				}
+				return server.SetFlags(testbedFlags(variant))
+			}),
+			integration.WithConnectionLoss(func() error {
+				server.InjectError(connect.CodeUnavailable, "connection lost")
+				server.DropStreams()
+				time.AfterFunc(500*time.Millisecond, server.ClearError)
+				return nil
+			}),
+			integration.WithUnavailableProviderSupplier(func() openfeature.FeatureProvider {
+				return flagd.NewProvider(
+					flagd.WithPort(1),
+					flagd.WithEventStreamConnectionMaxAttempts(1),
+					flagd.WithRetryBackoff(10*time.Millisecond, 10*time.Millisecond))
+			}),
+		),
+		ScenarioInitializer: integration.InitializeFlagdScenario,
+		Options: &godog.Options{
+			Format:   "pretty",
+			Paths:    paths,
+			TestingT: t, // Testing instance that will run subtests.
+			Strict:   true,
+		},
+	}
+
+	if testSuite.Run() != 0 {
+		t.Fatal("non-zero status returned, failed to run flagd tests")
+	}
+}
+
+func TestFlagdFakeInRPC(t *testing.T) {
+	runWithFake(t, []string{
+		"../flagd-testbed/gherkin/flagd.feature",
+		"../flagd-testbed/gherkin/flagd-rpc-caching.feature",
+		"../flagd-testbed/gherkin/flagd-reconnect.feature",
+	}, func(server *flagdtest.Server) openfeature.FeatureProvider {
+		return flagd.NewProvider(
+			flagd.WithHost(server.Host()),
+			flagd.WithPort(server.EvaluationPort()),
+			flagd.WithRetryBackoff(10*time.Millisecond, 50*time.Millisecond),
+			flagd.WithRetryGracePeriod(100*time.Millisecond),
+			flagd.WithUnlimitedRetries())
+	})
+}
+
+func TestFlagdFakeInProcess(t *testing.T) {
+	runWithFake(t, []string{
+		"../flagd-testbed/gherkin/flagd.feature",
+		"../flagd-testbed/gherkin/flagd-reconnect.feature",
+	}, func(server *flagdtest.Server) openfeature.FeatureProvider {
+		return flagd.NewProvider(
+			flagd.WithInProcessResolver(),
+			flagd.WithHost(server.Host()),
+			flagd.WithPort(server.SyncPort()),
+			flagd.WithRetryBackoff(10*time.Millisecond, 50*time.Millisecond),
+			flagd.WithRetryGracePeriod(100*time.Millisecond),
+			flagd.WithUnlimitedRetries())
+	})
+}
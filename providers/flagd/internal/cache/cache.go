@@ -1,6 +1,9 @@
 package cache
 
 import (
+	"sync/atomic"
+	"time"
+
 	"github.com/go-logr/logr"
 	lru "github.com/hashicorp/golang-lru/v2"
 )
@@ -10,6 +13,7 @@ type Type string
 const (
 	LRUValue      Type = "lru"
 	InMemValue    Type = "mem"
+	TTLValue      Type = "ttl"
 	DisabledValue Type = "disabled"
 )
 
@@ -19,14 +23,24 @@ type Cache[K comparable, V any] interface {
 	Purge()
 	Get(K) (value V, ok bool)
 	Remove(K) (present bool)
+	Keys() []K
+}
+
+// expiring is implemented by caches which expire their entries
+type expiring interface {
+	OnExpiry(func())
+	Close()
 }
 
 type Service struct {
-	cacheEnabled bool
-	cache        Cache[string, interface{}]
+	cacheAvailable bool
+	cacheEnabled   atomic.Bool
+	cache          Cache[string, interface{}]
 }
 
-func NewCacheService(cacheType Type, maxCacheSize int, log logr.Logger) *Service {
+// NewCacheService creates a cache of the given type. The ttl is only applicable to TTLValue cache type, which bounds
+// the staleness of each entry as well as the number of entries through maxCacheSize
+func NewCacheService(cacheType Type, maxCacheSize int, ttl time.Duration, log logr.Logger) *Service {
 	var c Cache[string, interface{}]
 	var err error
 	var cacheEnabled bool
@@ -43,16 +57,22 @@ func NewCacheService(cacheType Type, maxCacheSize int, log logr.Logger) *Service
 	case InMemValue:
 		c = NewInMemory[string, interface{}]()
 		cacheEnabled = true
+	case TTLValue:
+		c = NewInMemoryWithTTL[string, interface{}](ttl, maxCacheSize)
+		cacheEnabled = true
 	case DisabledValue:
 	default:
 		cacheEnabled = false
 		c = nil
 	}
 
-	return &Service{
-		cacheEnabled: cacheEnabled,
-		cache:        c,
+	s := &Service{
+		cacheAvailable: cacheEnabled,
+		cache:          c,
 	}
+	s.cacheEnabled.Store(cacheEnabled)
+
+	return s
 }
 
 func (s *Service) GetCache() Cache[string, interface{}] {
@@ -60,12 +80,32 @@ func (s *Service) GetCache() Cache[string, interface{}] {
 }
 
 func (s *Service) IsEnabled() bool {
-	return s.cacheEnabled
+	return s.cacheEnabled.Load()
 }
 
 func (s *Service) Disable() {
-	if s.IsEnabled() {
-		s.cacheEnabled = false
+	if s.cacheEnabled.CompareAndSwap(true, false) {
 		s.cache.Purge()
 	}
 }
+
+// Enable re-enables a previously disabled cache. Caches configured as disabled remain disabled
+func (s *Service) Enable() {
+	if s.cacheAvailable {
+		s.cacheEnabled.Store(true)
+	}
+}
+
+// OnExpiry sets a function called for each entry removed due to its expiry. Never called for caches without expiry
+func (s *Service) OnExpiry(onExpiry func()) {
+	if e, ok := s.cache.(expiring); ok {
+		e.OnExpiry(onExpiry)
+	}
+}
+
+// Close releases background resources held by the cache
+func (s *Service) Close() {
+	if e, ok := s.cache.(expiring); ok {
+		e.Close()
+	}
+}
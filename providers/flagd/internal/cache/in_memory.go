@@ -1,36 +1,112 @@
 package cache
 
 import (
+	"container/list"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// minSweepInterval bounds the frequency of background sweeping for short time-to-live values
+const minSweepInterval = 100 * time.Millisecond
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+	// element is the position of the entry in the expiry order, if the store tracks it
+	element *list.Element
+}
+
+// expired checks whether the entry expired at the given time. Entries without an expiry never expire
+func (e entry[V]) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
 type InMemory[K comparable, V any] struct {
-	values map[K]V
+	values map[K]entry[V]
 	rwMux  *sync.RWMutex
+	// order holds the keys of entries ordered by their expiry, which is the order of their addition as all entries
+	// share the same time-to-live. Only tracked by stores with a time-to-live or a maximum size
+	order *list.List
+
+	ttl      time.Duration
+	maxSize  int
+	onExpiry atomic.Pointer[func()]
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
 func NewInMemory[K comparable, V any]() *InMemory[K, V] {
 	return &InMemory[K, V]{
-		values: make(map[K]V),
+		values: make(map[K]entry[V]),
 		rwMux:  &sync.RWMutex{},
 	}
 }
 
+// NewInMemoryWithTTL creates an in memory store where each entry expires once the time-to-live elapsed since it was
+// added. Expired entries are never served and get removed by a background sweeper, which runs until Close is called.
+// If maxSize is positive, adding a new entry to a full store evicts the entry closest to its expiry.
+func NewInMemoryWithTTL[K comparable, V any](ttl time.Duration, maxSize int) *InMemory[K, V] {
+	m := &InMemory[K, V]{
+		values:  make(map[K]entry[V]),
+		rwMux:   &sync.RWMutex{},
+		order:   list.New(),
+		ttl:     ttl,
+		maxSize: maxSize,
+		stop:    make(chan struct{}),
+	}
+
+	if ttl > 0 {
+		go m.sweep(max(ttl, minSweepInterval))
+	}
+
+	return m
+}
+
 func (m *InMemory[K, V]) Add(flagKey K, value V) (evicted bool) {
 	m.rwMux.Lock()
 	defer m.rwMux.Unlock()
-	m.values[flagKey] = value
 
-	return false
+	e := entry[V]{value: value}
+	if m.ttl > 0 {
+		e.expiresAt = time.Now().Add(m.ttl)
+	}
+
+	existing, ok := m.values[flagKey]
+	if !ok && m.maxSize > 0 && len(m.values) >= m.maxSize {
+		m.evictOldest()
+		evicted = true
+	}
+
+	if m.order != nil {
+		if ok {
+			e.element = existing.element
+			m.order.MoveToBack(e.element)
+		} else {
+			e.element = m.order.PushBack(flagKey)
+		}
+	}
+
+	m.values[flagKey] = e
+
+	return evicted
 }
 
 func (m *InMemory[K, V]) Get(flagKey K) (value V, ok bool) {
 	m.rwMux.RLock()
-	defer m.rwMux.RUnlock()
+	e, ok := m.values[flagKey]
+	m.rwMux.RUnlock()
+
+	if !ok {
+		return value, false
+	}
 
-	val, ok := m.values[flagKey]
+	if e.expired(time.Now()) {
+		m.removeExpired(flagKey)
+		return value, false
+	}
 
-	return val, ok
+	return e.value, true
 }
 
 func (m *InMemory[K, V]) Remove(flagKey K) (present bool) {
@@ -38,7 +114,7 @@ func (m *InMemory[K, V]) Remove(flagKey K) (present bool) {
 	defer m.rwMux.Unlock()
 
 	_, ok := m.values[flagKey]
-	delete(m.values, flagKey)
+	m.delete(flagKey)
 	return ok
 }
 
@@ -46,5 +122,105 @@ func (m *InMemory[K, V]) Purge() {
 	m.rwMux.Lock()
 	defer m.rwMux.Unlock()
 
-	m.values = make(map[K]V)
+	m.values = make(map[K]entry[V])
+	if m.order != nil {
+		m.order.Init()
+	}
+}
+
+func (m *InMemory[K, V]) Keys() []K {
+	m.rwMux.RLock()
+	defer m.rwMux.RUnlock()
+
+	now := time.Now()
+	keys := make([]K, 0, len(m.values))
+	for k, e := range m.values {
+		if !e.expired(now) {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
+
+// OnExpiry sets a function called for each entry removed due to its expiry. It is called while the store is locked,
+// hence it must not access the store
+func (m *InMemory[K, V]) OnExpiry(onExpiry func()) {
+	m.onExpiry.Store(&onExpiry)
+}
+
+// Close stops background sweeping. Entries continue to expire on access
+func (m *InMemory[K, V]) Close() {
+	if m.stop == nil {
+		return
+	}
+
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+}
+
+// removeExpired removes the entry if it is still expired once the write lock is held
+func (m *InMemory[K, V]) removeExpired(flagKey K) {
+	m.rwMux.Lock()
+	defer m.rwMux.Unlock()
+
+	if e, ok := m.values[flagKey]; ok && e.expired(time.Now()) {
+		m.delete(flagKey)
+		m.notifyExpiry()
+	}
+}
+
+// notifyExpiry calls the expiry function, if set, for an entry removed due to its expiry
+func (m *InMemory[K, V]) notifyExpiry() {
+	if onExpiry := m.onExpiry.Load(); onExpiry != nil && *onExpiry != nil {
+		(*onExpiry)()
+	}
+}
+
+// evictOldest removes the entry closest to its expiry. Caller must hold the write lock
+func (m *InMemory[K, V]) evictOldest() {
+	if oldest := m.order.Front(); oldest != nil {
+		m.delete(oldest.Value.(K))
+	}
+}
+
+// delete removes the entry and its position in the expiry order. Caller must hold the write lock
+func (m *InMemory[K, V]) delete(flagKey K) {
+	if e, ok := m.values[flagKey]; ok && e.element != nil {
+		m.order.Remove(e.element)
+	}
+	delete(m.values, flagKey)
+}
+
+// sweep periodically removes expired entries until the store is closed
+func (m *InMemory[K, V]) sweep(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.removeAllExpired()
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+func (m *InMemory[K, V]) removeAllExpired() {
+	m.rwMux.Lock()
+	defer m.rwMux.Unlock()
+
+	// entries are ordered by their expiry, hence sweeping stops at the first entry which did not expire
+	now := time.Now()
+	for oldest := m.order.Front(); oldest != nil; oldest = m.order.Front() {
+		flagKey := oldest.Value.(K)
+		if !m.values[flagKey].expired(now) {
+			return
+		}
+
+		m.delete(flagKey)
+		m.notifyExpiry()
+	}
 }
@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countExpiries counts the entries the store removes due to their expiry
+func countExpiries[K comparable, V any](store *InMemory[K, V]) *atomic.Uint64 {
+	var expired atomic.Uint64
+	store.OnExpiry(func() {
+		expired.Add(1)
+	})
+	return &expired
+}
+
+func TestInMemoryWithTTL(t *testing.T) {
+	t.Run("entries expire after time-to-live", func(t *testing.T) {
+		store := NewInMemoryWithTTL[string, int](50*time.Millisecond, 0)
+		defer store.Close()
+		expired := countExpiries(store)
+
+		store.Add("key", 1)
+		if value, ok := store.Get("key"); !ok || value != 1 {
+			t.Fatalf("expected cached value 1, got %v (found: %v)", value, ok)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+
+		if _, ok := store.Get("key"); ok {
+			t.Fatal("expected entry to be expired")
+		}
+
+		if expired.Load() != 1 {
+			t.Errorf("expected 1 expired entry, got %d", expired.Load())
+		}
+	})
+
+	t.Run("expired entries are swept in background", func(t *testing.T) {
+		store := NewInMemoryWithTTL[string, int](10*time.Millisecond, 0)
+		defer store.Close()
+		expired := countExpiries(store)
+
+		store.Add("a", 1)
+		store.Add("b", 2)
+
+		time.Sleep(3 * minSweepInterval)
+
+		store.rwMux.RLock()
+		size := len(store.values)
+		store.rwMux.RUnlock()
+
+		if size != 0 {
+			t.Errorf("expected sweeper to remove expired entries, but %d remain", size)
+		}
+
+		if expired.Load() != 2 {
+			t.Errorf("expected 2 expired entries, got %d", expired.Load())
+		}
+	})
+
+	t.Run("size limit evicts entry closest to expiry", func(t *testing.T) {
+		store := NewInMemoryWithTTL[string, int](time.Minute, 2)
+		defer store.Close()
+
+		store.Add("a", 1)
+		store.Add("b", 2)
+		if evicted := store.Add("c", 3); !evicted {
+			t.Error("expected an eviction when adding to a full store")
+		}
+
+		if _, ok := store.Get("a"); ok {
+			t.Error("expected oldest entry to be evicted")
+		}
+
+		if len(store.Keys()) != 2 {
+			t.Errorf("expected 2 entries, got %d", len(store.Keys()))
+		}
+	})
+
+	t.Run("re-added entry is evicted last", func(t *testing.T) {
+		store := NewInMemoryWithTTL[string, int](time.Minute, 2)
+		defer store.Close()
+
+		store.Add("a", 1)
+		store.Add("b", 2)
+		store.Add("a", 3)
+		store.Add("c", 4)
+
+		if _, ok := store.Get("b"); ok {
+			t.Error("expected the entry closest to expiry to be evicted")
+		}
+
+		if value, ok := store.Get("a"); !ok || value != 3 {
+			t.Errorf("expected re-added entry to be kept, got %v (found: %v)", value, ok)
+		}
+
+		store.Remove("a")
+		store.Purge()
+		store.Add("d", 5)
+		if len(store.Keys()) != 1 {
+			t.Errorf("expected 1 entry, got %d", len(store.Keys()))
+		}
+	})
+}
+
+func TestInMemoryWithoutTTL(t *testing.T) {
+	store := NewInMemory[string, int]()
+	defer store.Close()
+	expired := countExpiries(store)
+
+	store.Add("key", 1)
+	if value, ok := store.Get("key"); !ok || value != 1 {
+		t.Fatalf("expected cached value 1, got %v (found: %v)", value, ok)
+	}
+
+	if expired.Load() != 0 {
+		t.Errorf("expected no expired entries, got %d", expired.Load())
+	}
+}
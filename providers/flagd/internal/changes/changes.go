@@ -0,0 +1,46 @@
+package changes
+
+import "github.com/open-feature/flagd/core/pkg/model"
+
+const (
+	// MetadataKey is the event metadata key of the flag changes of a configuration change event
+	MetadataKey = "changes"
+
+	// TypeKey is the key of the change type of a flag change
+	TypeKey = "type"
+	// OldDefaultVariantKey is the key of the default variant before the change, if known
+	OldDefaultVariantKey = "oldDefaultVariant"
+	// NewDefaultVariantKey is the key of the default variant after the change, if known
+	NewDefaultVariantKey = "newDefaultVariant"
+
+	Added   = "added"
+	Updated = "updated"
+	Removed = "removed"
+)
+
+// TypeOf maps the type of a flagd state change notification to a change type. Unknown types are reported as updates
+func TypeOf(notificationType string) string {
+	switch model.StateChangeNotificationType(notificationType) {
+	case model.NotificationCreate:
+		return Added
+	case model.NotificationDelete:
+		return Removed
+	default:
+		return Updated
+	}
+}
+
+// Change returns the metadata of a flag change. Unknown default variants are omitted
+func Change(changeType string, oldDefaultVariant string, newDefaultVariant string) map[string]interface{} {
+	change := map[string]interface{}{TypeKey: changeType}
+
+	if oldDefaultVariant != "" {
+		change[OldDefaultVariantKey] = oldDefaultVariant
+	}
+
+	if newDefaultVariant != "" {
+		change[NewDefaultVariantKey] = newDefaultVariant
+	}
+
+	return change
+}
@@ -0,0 +1,154 @@
+package headers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc/credentials"
+)
+
+const authorizationHeader = "Authorization"
+
+// TokenSource returns the bearer token attached to each request. It is invoked per request, hence implementations
+// are responsible to cache and refresh tokens
+type TokenSource func(ctx context.Context) (string, error)
+
+// Injector attaches static headers and a bearer token obtained from a TokenSource to requests made to flagd
+type Injector struct {
+	static      map[string]string
+	tokenSource TokenSource
+}
+
+// New creates an Injector. Returns nil if neither headers nor a token source are given
+func New(static map[string]string, tokenSource TokenSource) *Injector {
+	if len(static) == 0 && tokenSource == nil {
+		return nil
+	}
+
+	copied := make(map[string]string, len(static))
+	for k, v := range static {
+		copied[k] = v
+	}
+
+	return &Injector{
+		static:      copied,
+		tokenSource: tokenSource,
+	}
+}
+
+// Get returns the headers of a request. The bearer token of the token source overrides a static authorization header
+func (i *Injector) Get(ctx context.Context) (map[string]string, error) {
+	headers := make(map[string]string, len(i.static)+1)
+	for k, v := range i.static {
+		headers[k] = v
+	}
+
+	if i.tokenSource != nil {
+		token, err := i.tokenSource(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error obtaining token: %w", err)
+		}
+
+		for k := range headers {
+			if strings.EqualFold(k, authorizationHeader) {
+				delete(headers, k)
+			}
+		}
+		headers[authorizationHeader] = "Bearer " + token
+	}
+
+	return headers, nil
+}
+
+// Interceptor returns a connect.Interceptor attaching the headers to unary and streaming requests
+func (i *Injector) Interceptor() connect.Interceptor {
+	return &interceptor{injector: i}
+}
+
+// PerRPCCredentials returns gRPC credentials attaching the headers to each call. Headers are attached to insecure
+// connections as well, as flagd may be placed behind a proxy terminating TLS
+func (i *Injector) PerRPCCredentials() credentials.PerRPCCredentials {
+	return &perRPCCredentials{injector: i}
+}
+
+type interceptor struct {
+	injector *Injector
+}
+
+func (c *interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, request connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := c.apply(ctx, request.Header()); err != nil {
+			return nil, err
+		}
+
+		return next(ctx, request)
+	}
+}
+
+func (c *interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		conn := next(ctx, spec)
+		if err := c.apply(ctx, conn.RequestHeader()); err != nil {
+			return &failedStreamingClientConn{StreamingClientConn: conn, err: err}
+		}
+
+		return conn
+	}
+}
+
+func (c *interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}
+
+func (c *interceptor) apply(ctx context.Context, header http.Header) error {
+	headers, err := c.injector.Get(ctx)
+	if err != nil {
+		return connect.NewError(connect.CodeUnauthenticated, err)
+	}
+
+	for k, v := range headers {
+		header.Set(k, v)
+	}
+
+	return nil
+}
+
+// failedStreamingClientConn fails a stream for which headers could not be obtained, without contacting flagd
+type failedStreamingClientConn struct {
+	connect.StreamingClientConn
+	err error
+}
+
+func (f *failedStreamingClientConn) Send(any) error {
+	return f.err
+}
+
+func (f *failedStreamingClientConn) Receive(any) error {
+	return f.err
+}
+
+type perRPCCredentials struct {
+	injector *Injector
+}
+
+func (p *perRPCCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	headers, err := p.injector.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// gRPC metadata keys are lowercase
+	metadata := make(map[string]string, len(headers))
+	for k, v := range headers {
+		metadata[strings.ToLower(k)] = v
+	}
+
+	return metadata, nil
+}
+
+func (p *perRPCCredentials) RequireTransportSecurity() bool {
+	return false
+}
@@ -0,0 +1,123 @@
+package headers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	schemaConnectV1 "buf.build/gen/go/open-feature/flagd/connectrpc/go/flagd/evaluation/v1/evaluationv1connect"
+	schemaV1 "buf.build/gen/go/open-feature/flagd/protocolbuffers/go/flagd/evaluation/v1"
+	"connectrpc.com/connect"
+)
+
+func TestGet(t *testing.T) {
+	injector := New(map[string]string{
+		"x-tenant":      "team-a",
+		"authorization": "Bearer static",
+	}, func(context.Context) (string, error) {
+		return "dynamic", nil
+	})
+
+	headers, err := injector.Get(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(headers) != 2 || headers["x-tenant"] != "team-a" || headers["Authorization"] != "Bearer dynamic" {
+		t.Errorf("unexpected headers %v", headers)
+	}
+}
+
+func TestNewWithoutHeaders(t *testing.T) {
+	if New(nil, nil) != nil {
+		t.Error("expected no injector without headers and token source")
+	}
+}
+
+func TestInterceptor(t *testing.T) {
+	var mtx sync.Mutex
+	received := map[string]string{}
+
+	_, handler := schemaConnectV1.NewServiceHandler(schemaConnectV1.UnimplementedServiceHandler{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		received[r.URL.Path] = r.Header.Get("Authorization")
+		mtx.Unlock()
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	token := "first"
+	injector := New(nil, func(context.Context) (string, error) {
+		return token, nil
+	})
+
+	client := schemaConnectV1.NewServiceClient(server.Client(), server.URL,
+		connect.WithInterceptors(injector.Interceptor()))
+
+	_, _ = client.ResolveBoolean(context.Background(), connect.NewRequest(&schemaV1.ResolveBooleanRequest{}))
+
+	token = "second"
+	stream, err := client.EventStream(context.Background(), connect.NewRequest(&schemaV1.EventStreamRequest{}))
+	if err == nil {
+		for stream.Receive() {
+		}
+		_ = stream.Close()
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if got := received[schemaConnectV1.ServiceResolveBooleanProcedure]; got != "Bearer first" {
+		t.Errorf("expected unary request to carry the token, got %q", got)
+	}
+
+	if got := received[schemaConnectV1.ServiceEventStreamProcedure]; got != "Bearer second" {
+		t.Errorf("expected event stream request to carry the refreshed token, got %q", got)
+	}
+}
+
+func TestInterceptorTokenError(t *testing.T) {
+	injector := New(nil, func(context.Context) (string, error) {
+		return "", errors.New("token unavailable")
+	})
+
+	client := schemaConnectV1.NewServiceClient(http.DefaultClient, "http://localhost:0",
+		connect.WithInterceptors(injector.Interceptor()))
+
+	_, err := client.ResolveBoolean(context.Background(), connect.NewRequest(&schemaV1.ResolveBooleanRequest{}))
+	if connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Errorf("expected unauthenticated error, got %v", err)
+	}
+
+	stream, err := client.EventStream(context.Background(), connect.NewRequest(&schemaV1.EventStreamRequest{}))
+	if err == nil {
+		stream.Receive()
+		err = stream.Err()
+	}
+	if connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Errorf("expected unauthenticated stream error, got %v", err)
+	}
+}
+
+func TestPerRPCCredentials(t *testing.T) {
+	credentials := New(map[string]string{"X-Tenant": "team-a"}, func(context.Context) (string, error) {
+		return "token", nil
+	}).PerRPCCredentials()
+
+	metadata, err := credentials.GetRequestMetadata(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if metadata["x-tenant"] != "team-a" || metadata["authorization"] != "Bearer token" {
+		t.Errorf("unexpected metadata %v", metadata)
+	}
+
+	if credentials.RequireTransportSecurity() {
+		t.Error("expected credentials to be usable with insecure connections")
+	}
+}
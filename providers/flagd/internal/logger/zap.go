@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"sort"
+
+	"github.com/go-logr/logr"
+	flagdLogger "github.com/open-feature/flagd/core/pkg/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewFlagdLogger derives a flagd core logger writing to l. This routes logs of flagd core components, such as the
+// evaluator and sync providers, through the provider's logger. zap levels map to the verbosity levels of this package,
+// while errors are logged with logr.Logger.Error
+func NewFlagdLogger(l logr.Logger) *flagdLogger.Logger {
+	return flagdLogger.NewLogger(zap.New(&logrCore{log: l}), false)
+}
+
+// logrCore is a zapcore.Core writing entries to a logr.Logger
+type logrCore struct {
+	log logr.Logger
+}
+
+func (c *logrCore) Enabled(level zapcore.Level) bool {
+	if level >= zapcore.ErrorLevel {
+		return true
+	}
+
+	return c.log.V(verbosity(level)).Enabled()
+}
+
+func (c *logrCore) With(fields []zapcore.Field) zapcore.Core {
+	keysAndValues, _ := encode(fields)
+	return &logrCore{log: c.log.WithValues(keysAndValues...)}
+}
+
+func (c *logrCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+func (c *logrCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	keysAndValues, err := encode(fields)
+
+	if entry.Level >= zapcore.ErrorLevel {
+		c.log.Error(err, entry.Message, keysAndValues...)
+		return nil
+	}
+
+	if err != nil {
+		keysAndValues = append(keysAndValues, "error", err)
+	}
+
+	c.log.V(verbosity(entry.Level)).Info(entry.Message, keysAndValues...)
+	return nil
+}
+
+func (c *logrCore) Sync() error {
+	return nil
+}
+
+// verbosity maps zap levels below error to the verbosity levels of this package
+func verbosity(level zapcore.Level) int {
+	switch {
+	case level >= zapcore.WarnLevel:
+		return Warn
+	case level == zapcore.InfoLevel:
+		return Info
+	default:
+		return Debug
+	}
+}
+
+// encode converts zap fields to logr key value pairs, sorted by key. The first error field is returned separately
+func encode(fields []zapcore.Field) ([]interface{}, error) {
+	var err error
+	encoder := zapcore.NewMapObjectEncoder()
+
+	for _, field := range fields {
+		if fieldErr, ok := field.Interface.(error); ok && field.Type == zapcore.ErrorType && err == nil {
+			err = fieldErr
+			continue
+		}
+
+		field.AddTo(encoder)
+	}
+
+	keys := make([]string, 0, len(encoder.Fields))
+	for k := range encoder.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	keysAndValues := make([]interface{}, 0, 2*len(keys))
+	for _, k := range keys {
+		keysAndValues = append(keysAndValues, k, encoder.Fields[k])
+	}
+
+	return keysAndValues, err
+}
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"go.uber.org/zap"
+)
+
+func TestFlagdLogger(t *testing.T) {
+	var lines []string
+	sink := funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{Verbosity: Info})
+
+	log := NewFlagdLogger(sink)
+
+	log.Debug("debug line")
+	log.Info("info line", zap.String("source", "file"))
+	log.Warn("warn line")
+	log.Error("error line", zap.Error(errors.New("broken")))
+	log.WithFields(zap.String("component", "sync")).Info("child line")
+
+	expected := []string{
+		`"level"=1 "msg"="info line" "source"="file"`,
+		`"level"=0 "msg"="warn line"`,
+		`"msg"="error line" "error"="broken"`,
+		`"level"=1 "msg"="child line" "component"="sync"`,
+	}
+
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d: %v", len(expected), len(lines), lines)
+	}
+
+	for i, want := range expected {
+		if lines[i] != want {
+			t.Errorf("line %d: expected %s, got %s", i, want, lines[i])
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package observer
+
+import "time"
+
+// Observer is notified about the cache, the connection and the flag syncs of the provider. Implementations must be
+// safe for concurrent use, and must not block as they are called on the evaluation and connection paths
+type Observer interface {
+	// CacheHit is called when an evaluation is served from the cache
+	CacheHit(flagKey string)
+	// CacheMiss is called when an evaluation with an enabled cache is not served from the cache
+	CacheMiss(flagKey string)
+	// CacheEviction is called when an entry is evicted from a full cache
+	CacheEviction()
+	// CacheExpiry is called when an entry is removed from the cache as its time-to-live elapsed
+	CacheExpiry()
+	// CachePurge is called when all entries of the cache are removed
+	CachePurge()
+	// CacheStatus is called when the cache is disabled after a lost connection, or re-enabled once reconnected
+	CacheStatus(enabled bool)
+	// Connected is called when the connection to the source is established
+	Connected(source string)
+	// Disconnected is called when an established connection to the source is lost
+	Disconnected(source string)
+	// RetryAttempt is called when a failed connection attempt to the source is retried after the delay
+	RetryAttempt(source string, delay time.Duration)
+	// SyncUpdate is called when flags of the source changed
+	SyncUpdate(source string, changedFlags int)
+}
+
+// Noop is an Observer ignoring all notifications. Embed it to implement a subset of Observer
+type Noop struct{}
+
+func (Noop) CacheHit(string)                    {}
+func (Noop) CacheMiss(string)                   {}
+func (Noop) CacheEviction()                     {}
+func (Noop) CacheExpiry()                       {}
+func (Noop) CachePurge()                        {}
+func (Noop) CacheStatus(bool)                   {}
+func (Noop) Connected(string)                   {}
+func (Noop) Disconnected(string)                {}
+func (Noop) RetryAttempt(string, time.Duration) {}
+func (Noop) SyncUpdate(string, int)             {}
+
+// OrNoop returns the observer, or Noop if it is nil
+func OrNoop(o Observer) Observer {
+	if o == nil {
+		return Noop{}
+	}
+
+	return o
+}
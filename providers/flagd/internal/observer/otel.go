@@ -0,0 +1,124 @@
+package observer
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	meterName = "github.com/open-feature/go-sdk-contrib/providers/flagd"
+
+	sourceKey = attribute.Key("feature_flag.flagd.source")
+)
+
+// Otel is an Observer recording notifications as OpenTelemetry metrics
+type Otel struct {
+	cacheHits      metric.Int64Counter
+	cacheMisses    metric.Int64Counter
+	cacheEvictions metric.Int64Counter
+	cacheExpiries  metric.Int64Counter
+	cachePurges    metric.Int64Counter
+	connects       metric.Int64Counter
+	disconnects    metric.Int64Counter
+	retries        metric.Int64Counter
+	syncUpdates    metric.Int64Counter
+	flagChanges    metric.Int64Counter
+
+	// cacheEnabled is observed by a gauge, as the cache status is reported repeatedly
+	cacheEnabled atomic.Int64
+}
+
+// NewOtel creates the instruments of the Otel observer with a meter of the provider
+func NewOtel(provider metric.MeterProvider) (*Otel, error) {
+	meter := provider.Meter(meterName)
+	o := &Otel{}
+
+	counters := []struct {
+		counter     *metric.Int64Counter
+		name        string
+		description string
+	}{
+		{&o.cacheHits, "feature_flag.flagd.cache.hits", "Evaluations served from the cache"},
+		{&o.cacheMisses, "feature_flag.flagd.cache.misses", "Evaluations not served from the enabled cache"},
+		{&o.cacheEvictions, "feature_flag.flagd.cache.evictions", "Entries evicted from the full cache"},
+		{&o.cacheExpiries, "feature_flag.flagd.cache.expirations", "Entries removed from the cache once expired"},
+		{&o.cachePurges, "feature_flag.flagd.cache.purges", "Removals of all entries of the cache"},
+		{&o.connects, "feature_flag.flagd.connection.connects", "Established connections"},
+		{&o.disconnects, "feature_flag.flagd.connection.disconnects", "Lost connections"},
+		{&o.retries, "feature_flag.flagd.connection.retries", "Retried connection attempts"},
+		{&o.syncUpdates, "feature_flag.flagd.sync.updates", "Flag syncs changing flags"},
+		{&o.flagChanges, "feature_flag.flagd.sync.flag_changes", "Flags changed by flag syncs"},
+	}
+
+	var errs []error
+	for _, c := range counters {
+		counter, err := meter.Int64Counter(c.name, metric.WithDescription(c.description))
+		errs = append(errs, err)
+		*c.counter = counter
+	}
+
+	_, err := meter.Int64ObservableGauge("feature_flag.flagd.cache.enabled",
+		metric.WithDescription("Whether the cache is enabled (1) or disabled (0)"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			observer.Observe(o.cacheEnabled.Load())
+			return nil
+		}))
+	errs = append(errs, err)
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+func (o *Otel) CacheHit(string) {
+	o.cacheHits.Add(context.Background(), 1)
+}
+
+func (o *Otel) CacheMiss(string) {
+	o.cacheMisses.Add(context.Background(), 1)
+}
+
+func (o *Otel) CacheEviction() {
+	o.cacheEvictions.Add(context.Background(), 1)
+}
+
+func (o *Otel) CacheExpiry() {
+	o.cacheExpiries.Add(context.Background(), 1)
+}
+
+func (o *Otel) CachePurge() {
+	o.cachePurges.Add(context.Background(), 1)
+}
+
+func (o *Otel) CacheStatus(enabled bool) {
+	if enabled {
+		o.cacheEnabled.Store(1)
+	} else {
+		o.cacheEnabled.Store(0)
+	}
+}
+
+func (o *Otel) Connected(source string) {
+	o.connects.Add(context.Background(), 1, metric.WithAttributes(sourceKey.String(source)))
+}
+
+func (o *Otel) Disconnected(source string) {
+	o.disconnects.Add(context.Background(), 1, metric.WithAttributes(sourceKey.String(source)))
+}
+
+func (o *Otel) RetryAttempt(source string, _ time.Duration) {
+	o.retries.Add(context.Background(), 1, metric.WithAttributes(sourceKey.String(source)))
+}
+
+func (o *Otel) SyncUpdate(source string, changedFlags int) {
+	attributes := metric.WithAttributes(sourceKey.String(source))
+	o.syncUpdates.Add(context.Background(), 1, attributes)
+	o.flagChanges.Add(context.Background(), int64(changedFlags), attributes)
+}
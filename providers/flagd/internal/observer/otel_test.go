@@ -0,0 +1,74 @@
+package observer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestOtel(t *testing.T) {
+	// given
+	reader := sdkmetric.NewManualReader()
+	o, err := NewOtel(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// when
+	o.CacheHit("a")
+	o.CacheHit("a")
+	o.CacheMiss("b")
+	o.CacheEviction()
+	o.CacheExpiry()
+	o.CachePurge()
+	o.CacheStatus(true)
+	o.Connected("localhost:8013")
+	o.RetryAttempt("localhost:8013", time.Second)
+	o.Disconnected("localhost:8013")
+	o.SyncUpdate("localhost:8013", 3)
+
+	// then
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]int64{}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, point := range data.DataPoints {
+					values[m.Name] += point.Value
+				}
+			case metricdata.Gauge[int64]:
+				for _, point := range data.DataPoints {
+					values[m.Name] = point.Value
+				}
+			}
+		}
+	}
+
+	expected := map[string]int64{
+		"feature_flag.flagd.cache.hits":             2,
+		"feature_flag.flagd.cache.misses":           1,
+		"feature_flag.flagd.cache.evictions":        1,
+		"feature_flag.flagd.cache.expirations":      1,
+		"feature_flag.flagd.cache.purges":           1,
+		"feature_flag.flagd.cache.enabled":          1,
+		"feature_flag.flagd.connection.connects":    1,
+		"feature_flag.flagd.connection.disconnects": 1,
+		"feature_flag.flagd.connection.retries":     1,
+		"feature_flag.flagd.sync.updates":           1,
+		"feature_flag.flagd.sync.flag_changes":      3,
+	}
+
+	for name, value := range expected {
+		if values[name] != value {
+			t.Errorf("expected %s to be %d, got %d", name, value, values[name])
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// GracePeriod tracks a lost connection. Once the connection is lost, onStale is invoked and the grace period starts.
+// If the connection is not restored before the grace period elapses, onExpiry is invoked. GracePeriod is safe for
+// concurrent use
+type GracePeriod struct {
+	period   time.Duration
+	onStale  func()
+	onExpiry func()
+
+	mtx        sync.Mutex
+	lost       bool
+	generation uint64
+	timer      *time.Timer
+}
+
+func NewGracePeriod(period time.Duration, onStale func(), onExpiry func()) *GracePeriod {
+	return &GracePeriod{
+		period:   period,
+		onStale:  onStale,
+		onExpiry: onExpiry,
+	}
+}
+
+// Lost marks the connection as lost and starts the grace period. Returns false if the connection is already
+// considered lost
+func (g *GracePeriod) Lost() bool {
+	g.mtx.Lock()
+	if g.lost {
+		g.mtx.Unlock()
+		return false
+	}
+
+	g.lost = true
+	generation := g.generation
+	g.mtx.Unlock()
+
+	// onStale may block, hence it is invoked without holding the lock
+	g.onStale()
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	// the grace period starts unless the connection was restored meanwhile
+	if g.lost && generation == g.generation {
+		g.timer = time.AfterFunc(g.period, func() {
+			g.expire(generation)
+		})
+	}
+
+	return true
+}
+
+// Restored marks the connection as restored and stops the grace period. Returns true if the connection was
+// considered lost
+func (g *GracePeriod) Restored() bool {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	wasLost := g.lost
+	g.lost = false
+	g.stop()
+
+	return wasLost
+}
+
+// IsLost returns whether the connection is considered lost, regardless of the grace period having elapsed
+func (g *GracePeriod) IsLost() bool {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	return g.lost
+}
+
+// Stop the grace period without invoking onExpiry
+func (g *GracePeriod) Stop() {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	g.stop()
+}
+
+// stop the timer and invalidate pending expiries. Caller must hold the lock
+func (g *GracePeriod) stop() {
+	g.generation++
+	if g.timer != nil {
+		g.timer.Stop()
+		g.timer = nil
+	}
+}
+
+func (g *GracePeriod) expire(generation uint64) {
+	g.mtx.Lock()
+	if !g.lost || generation != g.generation {
+		g.mtx.Unlock()
+		return
+	}
+	g.timer = nil
+	g.mtx.Unlock()
+
+	g.onExpiry()
+}
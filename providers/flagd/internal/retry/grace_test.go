@@ -0,0 +1,109 @@
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGracePeriodExpiry(t *testing.T) {
+	stale := make(chan struct{}, 2)
+	expired := make(chan struct{}, 2)
+
+	grace := NewGracePeriod(50*time.Millisecond, func() {
+		stale <- struct{}{}
+	}, func() {
+		expired <- struct{}{}
+	})
+
+	if !grace.Lost() {
+		t.Fatal("expected first loss to start the grace period")
+	}
+
+	if grace.Lost() {
+		t.Fatal("expected repeated loss to be ignored")
+	}
+
+	if len(stale) != 1 {
+		t.Fatalf("expected stale callback to be invoked once, got %d", len(stale))
+	}
+
+	select {
+	case <-expired:
+	case <-time.After(time.Second):
+		t.Fatal("expected grace period to expire")
+	}
+
+	if !grace.IsLost() {
+		t.Error("expected connection to remain lost after expiry")
+	}
+
+	if !grace.Restored() {
+		t.Error("expected restore to report the lost connection")
+	}
+
+	if grace.IsLost() {
+		t.Error("expected connection to be restored")
+	}
+}
+
+func TestGracePeriodRestored(t *testing.T) {
+	expired := make(chan struct{}, 1)
+
+	grace := NewGracePeriod(50*time.Millisecond, func() {}, func() {
+		expired <- struct{}{}
+	})
+
+	if grace.Restored() {
+		t.Error("expected restore without loss to report no lost connection")
+	}
+
+	grace.Lost()
+	if !grace.Restored() {
+		t.Error("expected restore to report the lost connection")
+	}
+
+	select {
+	case <-expired:
+		t.Fatal("expected grace period to be stopped on restore")
+	case <-time.After(150 * time.Millisecond):
+	}
+}
+
+func TestGracePeriodBlockingStale(t *testing.T) {
+	release := make(chan struct{})
+
+	// given - a stale callback blocking until its event is consumed
+	grace := NewGracePeriod(time.Minute, func() {
+		<-release
+	}, func() {})
+
+	lost := make(chan bool)
+	go func() {
+		lost <- grace.Lost()
+	}()
+
+	// then - the state of the connection is accessible meanwhile
+	done := make(chan struct{})
+	go func() {
+		for !grace.IsLost() {
+			time.Sleep(time.Millisecond)
+		}
+		grace.Restored()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the connection state to be accessible while the stale callback blocks")
+	}
+
+	close(release)
+	if !<-lost {
+		t.Error("expected loss to start the grace period")
+	}
+
+	if grace.IsLost() {
+		t.Error("expected connection to remain restored")
+	}
+}
@@ -0,0 +1,90 @@
+package retry
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	DefaultBaseDelay = time.Second
+	DefaultMaxDelay  = 120 * time.Second
+
+	factor = 2
+)
+
+// Policy defines the backoff between connection attempts
+type Policy struct {
+	// BaseDelay is the delay after the first failed attempt, which doubles with each further attempt
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts. No cap is applied if zero
+	MaxDelay time.Duration
+	// Jitter is the fraction [0, 1] of each delay which is randomized to spread attempts of multiple clients
+	Jitter float64
+	// MaxAttempts is the number of attempts before the connection is considered failed
+	MaxAttempts int
+	// Unlimited keeps retrying once MaxAttempts are exhausted. The delay keeps doubling up to MaxDelay, without bound if
+	// MaxDelay is zero
+	Unlimited bool
+}
+
+// Counter tracks connection attempts and delays according to a Policy. Counter is not safe for concurrent use
+type Counter struct {
+	policy Policy
+
+	currentDelay   time.Duration
+	currentRetries int
+}
+
+func NewCounter(policy Policy) *Counter {
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = DefaultBaseDelay
+	}
+
+	if policy.Jitter < 0 {
+		policy.Jitter = 0
+	} else if policy.Jitter > 1 {
+		policy.Jitter = 1
+	}
+
+	return &Counter{
+		policy:       policy,
+		currentDelay: policy.BaseDelay,
+	}
+}
+
+// Reset the retry counter and sleep delay
+func (c *Counter) Reset() {
+	c.currentDelay = c.policy.BaseDelay
+	c.currentRetries = 0
+}
+
+// Retry increments current retry attempts, check and return a boolean stating the attempt is within MaxAttempts
+func (c *Counter) Retry() bool {
+	c.currentRetries++
+	return c.currentRetries <= c.policy.MaxAttempts
+}
+
+// Unlimited returns whether attempts continue once MaxAttempts are exhausted
+func (c *Counter) Unlimited() bool {
+	return c.policy.Unlimited
+}
+
+// Sleep returns the current sleep delay, with jitter applied, and increment the next sleep value
+func (c *Counter) Sleep() time.Duration {
+	var value = c.currentDelay
+
+	c.currentDelay = factor * c.currentDelay
+	if c.policy.MaxDelay > 0 && c.currentDelay > c.policy.MaxDelay {
+		c.currentDelay = c.policy.MaxDelay
+	}
+
+	if c.policy.MaxDelay > 0 && value > c.policy.MaxDelay {
+		value = c.policy.MaxDelay
+	}
+
+	if c.policy.Jitter > 0 {
+		value -= time.Duration(rand.Float64() * c.policy.Jitter * float64(value))
+	}
+
+	return value
+}
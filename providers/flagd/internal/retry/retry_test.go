@@ -0,0 +1,64 @@
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCounterDelays(t *testing.T) {
+	counter := NewCounter(Policy{
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    300 * time.Millisecond,
+		MaxAttempts: 5,
+	})
+
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		300 * time.Millisecond,
+		300 * time.Millisecond,
+	}
+
+	for i, want := range expected {
+		if got := counter.Sleep(); got != want {
+			t.Errorf("attempt %d: expected delay %s, got %s", i+1, want, got)
+		}
+	}
+
+	counter.Reset()
+	if got := counter.Sleep(); got != 100*time.Millisecond {
+		t.Errorf("expected delay to reset to base delay, got %s", got)
+	}
+}
+
+func TestCounterJitter(t *testing.T) {
+	counter := NewCounter(Policy{
+		BaseDelay: time.Second,
+		MaxDelay:  time.Second,
+		Jitter:    0.5,
+	})
+
+	for i := 0; i < 100; i++ {
+		delay := counter.Sleep()
+		if delay > time.Second || delay < 500*time.Millisecond {
+			t.Fatalf("expected delay within jitter bounds, got %s", delay)
+		}
+	}
+}
+
+func TestCounterAttempts(t *testing.T) {
+	counter := NewCounter(Policy{MaxAttempts: 2})
+
+	if !counter.Retry() || !counter.Retry() {
+		t.Fatal("expected attempts within the limit to be allowed")
+	}
+
+	if counter.Retry() {
+		t.Error("expected attempts beyond the limit to be exhausted")
+	}
+
+	counter.Reset()
+	if !counter.Retry() {
+		t.Error("expected attempts to be allowed after a reset")
+	}
+}
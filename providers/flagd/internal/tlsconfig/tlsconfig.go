@@ -0,0 +1,169 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Options define the TLS configuration of connections to flagd
+type Options struct {
+	// CertificatePath is the path of the CA certificate used to verify flagd. System certificates are used if empty
+	CertificatePath string
+	// ClientCertPath and ClientKeyPath are the paths of the client key pair presented for mutual TLS
+	ClientCertPath string
+	ClientKeyPath  string
+	// ServerName overrides the name used to verify the certificate of flagd
+	ServerName string
+}
+
+// New derives a *tls.Config from the options. Certificate files are re-read on new connections once they changed on
+// disk, hence rotated certificates are picked up without restarting the provider. If a changed file can not be
+// loaded, e.g. because it is partially written, the previously loaded certificate remains in use.
+func New(opts Options) (*tls.Config, error) {
+	if (opts.ClientCertPath == "") != (opts.ClientKeyPath == "") {
+		return nil, errors.New("client certificate and key must be provided together")
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: opts.ServerName,
+	}
+
+	if opts.CertificatePath != "" {
+		roots := newReloader([]string{opts.CertificatePath}, func() (*x509.CertPool, error) {
+			return loadCertPool(opts.CertificatePath)
+		})
+
+		if _, err := roots.get(); err != nil {
+			return nil, err
+		}
+
+		// verification is done by verifyConnection, as tls.Config does not allow to replace RootCAs of a config in use
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			pool, err := roots.get()
+			if err != nil {
+				return err
+			}
+
+			return verifyConnection(state, pool)
+		}
+	}
+
+	if opts.ClientCertPath != "" {
+		keyPair := newReloader([]string{opts.ClientCertPath, opts.ClientKeyPath}, func() (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(opts.ClientCertPath, opts.ClientKeyPath)
+			if err != nil {
+				return nil, fmt.Errorf("error loading client certificate: %w", err)
+			}
+			return &cert, nil
+		})
+
+		if _, err := keyPair.get(); err != nil {
+			return nil, err
+		}
+
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return keyPair.get()
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	caCert, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	caCertPool := x509.NewCertPool()
+	if !caCertPool.AppendCertsFromPEM(caCert) {
+		return nil, errors.New("error appending provider certificate file. please check and try again")
+	}
+
+	return caCertPool, nil
+}
+
+// verifyConnection verifies the peer certificate chain against the pool, for the server name of the connection
+func verifyConnection(state tls.ConnectionState, pool *x509.CertPool) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("no certificate presented by flagd")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       state.ServerName,
+		Intermediates: intermediates,
+		Roots:         pool,
+	})
+
+	return err
+}
+
+// reloader loads a value from files and reloads it once the modification time of any file changed
+type reloader[T any] struct {
+	paths []string
+	load  func() (T, error)
+
+	mtx      sync.Mutex
+	modTimes []time.Time
+	value    T
+	loaded   bool
+}
+
+func newReloader[T any](paths []string, load func() (T, error)) *reloader[T] {
+	return &reloader[T]{
+		paths:    paths,
+		load:     load,
+		modTimes: make([]time.Time, len(paths)),
+	}
+}
+
+func (r *reloader[T]) get() (T, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	modTimes := make([]time.Time, len(r.paths))
+	changed := !r.loaded
+	for i, path := range r.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			if r.loaded {
+				// keep serving the loaded value while files are being replaced
+				return r.value, nil
+			}
+			return r.value, err
+		}
+
+		modTimes[i] = info.ModTime()
+		changed = changed || !modTimes[i].Equal(r.modTimes[i])
+	}
+
+	if !changed {
+		return r.value, nil
+	}
+
+	value, err := r.load()
+	if err != nil {
+		if r.loaded {
+			return r.value, nil
+		}
+		return r.value, err
+	}
+
+	r.value = value
+	r.modTimes = modTimes
+	r.loaded = true
+
+	return r.value, nil
+}
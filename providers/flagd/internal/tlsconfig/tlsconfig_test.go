@@ -0,0 +1,209 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCA(t)
+
+	serverCert := ca.issue(t, "flagd.internal", x509.ExtKeyUsageServerAuth)
+	clientCert := ca.issue(t, "client", x509.ExtKeyUsageClientAuth)
+
+	caPath := filepath.Join(dir, "ca.pem")
+	certPath := filepath.Join(dir, "client.pem")
+	keyPath := filepath.Join(dir, "client-key.pem")
+
+	writePEM(t, caPath, ca.certPEM)
+	writePEM(t, certPath, clientCert.certPEM)
+	writePEM(t, keyPath, clientCert.keyPEM)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	var presented []string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presented = append(presented, r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert.keyPair(t)},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	tlsConfig, err := New(Options{
+		CertificatePath: caPath,
+		ClientCertPath:  certPath,
+		ClientKeyPath:   keyPath,
+		ServerName:      "flagd.internal",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	get := func() error {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig, DisableKeepAlives: true}}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	if err := get(); err != nil {
+		t.Fatalf("expected mutual TLS connection to succeed, got %v", err)
+	}
+
+	// rotate the client certificate on disk
+	rotated := ca.issue(t, "rotated-client", x509.ExtKeyUsageClientAuth)
+	later := time.Now().Add(time.Minute)
+	writePEM(t, certPath, rotated.certPEM)
+	writePEM(t, keyPath, rotated.keyPEM)
+	for _, path := range []string{certPath, keyPath} {
+		if err := os.Chtimes(path, later, later); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := get(); err != nil {
+		t.Fatalf("expected connection with rotated certificate to succeed, got %v", err)
+	}
+
+	if len(presented) != 2 || presented[0] != "client" || presented[1] != "rotated-client" {
+		t.Errorf("expected rotated client certificate to be presented, got %v", presented)
+	}
+}
+
+func TestServerNameMismatch(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCA(t)
+	serverCert := ca.issue(t, "flagd.internal", x509.ExtKeyUsageServerAuth)
+
+	caPath := filepath.Join(dir, "ca.pem")
+	writePEM(t, caPath, ca.certPEM)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{serverCert.keyPair(t)}}
+	server.StartTLS()
+	defer server.Close()
+
+	tlsConfig, err := New(Options{CertificatePath: caPath, ServerName: "other.internal"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	if _, err := client.Get(server.URL); err == nil {
+		t.Fatal("expected verification to fail for a mismatching server name")
+	}
+}
+
+func TestIncompleteKeyPair(t *testing.T) {
+	if _, err := New(Options{ClientCertPath: "/client.pem"}); err == nil {
+		t.Fatal("expected error for client certificate without key")
+	}
+}
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func (c testCert) keyPair(t *testing.T) tls.Certificate {
+	keyPair, err := tls.X509KeyPair(c.certPEM, c.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyPair
+}
+
+func newCA(t *testing.T) testCert {
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	return createCert(t, template, nil)
+}
+
+func (c testCert) issue(t *testing.T, name string, usage x509.ExtKeyUsage) testCert {
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+
+	return createCert(t, template, &c)
+}
+
+func createCert(t *testing.T, template *x509.Certificate, issuer *testCert) testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parent, signer := template, key
+	if issuer != nil {
+		parent, signer = issuer.cert, issuer.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+	}
+}
+
+func writePEM(t *testing.T, path string, data []byte) {
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,345 @@
+package flagd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/cache"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidConfigFile is the cause of errors of config files which can not be read or contain invalid values
+var ErrInvalidConfigFile = errors.New("invalid flagd config file")
+
+// configFile is the structure of config files of FromConfigFile. Fields are pointers to distinguish absent values,
+// which keep the value of the configuration, from zero values
+type configFile struct {
+	Resolver               *string           `json:"resolver" yaml:"resolver"`
+	Host                   *string           `json:"host" yaml:"host"`
+	Port                   *int              `json:"port" yaml:"port"`
+	SocketPath             *string           `json:"socketPath" yaml:"socketPath"`
+	TLS                    *configFileTLS    `json:"tls" yaml:"tls"`
+	Headers                map[string]string `json:"headers" yaml:"headers"`
+	Protocol               *string           `json:"protocol" yaml:"protocol"`
+	Compression            *string           `json:"compression" yaml:"compression"`
+	H2C                    *bool             `json:"h2c" yaml:"h2c"`
+	OtelInterceptor        *bool             `json:"otelInterceptor" yaml:"otelInterceptor"`
+	Deadline               *string           `json:"deadline" yaml:"deadline"`
+	Cache                  *configFileCache  `json:"cache" yaml:"cache"`
+	Retry                  *configFileRetry  `json:"retry" yaml:"retry"`
+	Selector               *string           `json:"selector" yaml:"selector"`
+	Selectors              []string          `json:"selectors" yaml:"selectors"`
+	OfflineFlagSourcePaths []string          `json:"offlineFlagSourcePaths" yaml:"offlineFlagSourcePaths"`
+	SyncURL                *string           `json:"syncURL" yaml:"syncURL"`
+	SyncPollInterval       *string           `json:"syncPollInterval" yaml:"syncPollInterval"`
+	SnapshotPath           *string           `json:"snapshotPath" yaml:"snapshotPath"`
+	StrictValidation       *bool             `json:"strictValidation" yaml:"strictValidation"`
+}
+
+type configFileTLS struct {
+	Enabled        *bool   `json:"enabled" yaml:"enabled"`
+	CertPath       *string `json:"certPath" yaml:"certPath"`
+	ClientCertPath *string `json:"clientCertPath" yaml:"clientCertPath"`
+	ClientKeyPath  *string `json:"clientKeyPath" yaml:"clientKeyPath"`
+	ServerName     *string `json:"serverName" yaml:"serverName"`
+}
+
+type configFileCache struct {
+	Type                *string `json:"type" yaml:"type"`
+	MaxSize             *int    `json:"maxSize" yaml:"maxSize"`
+	TTL                 *string `json:"ttl" yaml:"ttl"`
+	ContextAware        *bool   `json:"contextAware" yaml:"contextAware"`
+	PersistencePath     *string `json:"persistencePath" yaml:"persistencePath"`
+	PersistenceInterval *string `json:"persistenceInterval" yaml:"persistenceInterval"`
+}
+
+type configFileRetry struct {
+	MaxAttempts *int     `json:"maxAttempts" yaml:"maxAttempts"`
+	Backoff     *string  `json:"backoff" yaml:"backoff"`
+	BackoffMax  *string  `json:"backoffMax" yaml:"backoffMax"`
+	Jitter      *float64 `json:"jitter" yaml:"jitter"`
+	Unlimited   *bool    `json:"unlimited" yaml:"unlimited"`
+	GracePeriod *string  `json:"gracePeriod" yaml:"gracePeriod"`
+}
+
+// FromConfigFile reads the configuration from a YAML (.yaml, .yml) or JSON (.json) file. Values of the file override
+// default values and environment variables, and are overridden by options following this option. Durations are
+// given in the format of time.ParseDuration, such as "1.5s".
+// Files which can not be read, unknown keys and invalid values fail the provider initialization with an error
+// wrapping ErrInvalidConfigFile, listing all invalid values
+func FromConfigFile(path string) ProviderOption {
+	return func(p *Provider) {
+		if err := p.providerConfiguration.updateFromConfigFile(path); err != nil {
+			p.logger.Error(err, "failed to load config file")
+			p.providerConfiguration.err = errors.Join(p.providerConfiguration.err, err)
+		}
+	}
+}
+
+// updateFromConfigFile applies the values of the config file. The configuration is only updated if all values of the
+// file are valid
+func (cfg *providerConfiguration) updateFromConfigFile(path string) error {
+	file, err := readConfigFile(path)
+	if err != nil {
+		return fmt.Errorf("%w %s: %w", ErrInvalidConfigFile, path, err)
+	}
+
+	updated := *cfg
+	if err := updated.apply(file); err != nil {
+		return fmt.Errorf("%w %s:\n%w", ErrInvalidConfigFile, path, err)
+	}
+
+	*cfg = updated
+	return nil
+}
+
+// readConfigFile decodes the config file by its extension, rejecting unknown keys
+func readConfigFile(path string) (configFile, error) {
+	var file configFile
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return file, err
+	}
+
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&file)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&file)
+	default:
+		return file, errors.New("unsupported file extension, expected .yaml, .yml or .json")
+	}
+
+	return file, err
+}
+
+// apply applies the values of the config file, collecting errors of all invalid values
+func (cfg *providerConfiguration) apply(file configFile) error {
+	var errs []error
+	invalid := func(key string, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+	duration := func(key string, value *string, positive bool, target *time.Duration) {
+		if value == nil {
+			return
+		}
+
+		d, err := time.ParseDuration(*value)
+		switch {
+		case err != nil:
+			invalid(key, "invalid duration %q", *value)
+		case d < 0 || positive && d == 0:
+			invalid(key, "must be positive, got %s", *value)
+		default:
+			*target = d
+		}
+	}
+
+	if file.Resolver != nil {
+		switch ResolverType(*file.Resolver) {
+		case rpc, inProcess:
+			cfg.Resolver = ResolverType(*file.Resolver)
+		default:
+			invalid("resolver", "must be one of %q, %q, got %q", rpc, inProcess, *file.Resolver)
+		}
+	}
+
+	if file.Host != nil {
+		cfg.Host = *file.Host
+	}
+
+	if file.Port != nil {
+		if *file.Port < 1 || *file.Port > 65535 {
+			invalid("port", "must be between 1 and 65535, got %d", *file.Port)
+		} else {
+			cfg.Port = uint16(*file.Port)
+		}
+	}
+
+	if file.SocketPath != nil {
+		cfg.SocketPath = *file.SocketPath
+	}
+
+	if tls := file.TLS; tls != nil {
+		if tls.CertPath != nil {
+			cfg.TLSEnabled = true
+			cfg.CertificatePath = *tls.CertPath
+		}
+
+		if (tls.ClientCertPath == nil) != (tls.ClientKeyPath == nil) {
+			invalid("tls", "clientCertPath and clientKeyPath must be provided together")
+		} else if tls.ClientCertPath != nil {
+			cfg.TLSEnabled = true
+			cfg.ClientCertPath = *tls.ClientCertPath
+			cfg.ClientKeyPath = *tls.ClientKeyPath
+		}
+
+		if tls.ServerName != nil {
+			cfg.TLSEnabled = true
+			cfg.ServerName = *tls.ServerName
+		}
+
+		// an explicit value wins over the values implying TLS
+		if tls.Enabled != nil {
+			cfg.TLSEnabled = *tls.Enabled
+		}
+	}
+
+	if file.Headers != nil {
+		cfg.Headers = file.Headers
+	}
+
+	if file.Protocol != nil {
+		switch Protocol(*file.Protocol) {
+		case ProtocolConnect, ProtocolGRPC, ProtocolGRPCWeb:
+			cfg.Protocol = Protocol(*file.Protocol)
+		default:
+			invalid("protocol", "must be one of %q, %q, %q, got %q",
+				ProtocolConnect, ProtocolGRPC, ProtocolGRPCWeb, *file.Protocol)
+		}
+	}
+
+	if file.Compression != nil {
+		switch *file.Compression {
+		case CompressionGzip:
+			cfg.Compression = CompressionGzip
+		case "none":
+			cfg.Compression = ""
+		default:
+			invalid("compression", "must be one of %q, %q, got %q", CompressionGzip, "none", *file.Compression)
+		}
+	}
+
+	if file.H2C != nil {
+		cfg.H2C = *file.H2C
+	}
+
+	if cfg.H2C && cfg.TLSEnabled && (file.H2C != nil || file.TLS != nil) {
+		invalid("h2c", "can not be combined with TLS")
+	}
+
+	if file.OtelInterceptor != nil {
+		cfg.OtelIntercept = *file.OtelInterceptor
+	}
+
+	duration("deadline", file.Deadline, false, &cfg.Deadline)
+
+	if c := file.Cache; c != nil {
+		if c.Type != nil {
+			switch cache.Type(*c.Type) {
+			case cache.LRUValue, cache.InMemValue, cache.TTLValue, cache.DisabledValue:
+				cfg.CacheType = cache.Type(*c.Type)
+			default:
+				invalid("cache.type", "must be one of %q, %q, %q, %q, got %q",
+					cache.LRUValue, cache.InMemValue, cache.TTLValue, cache.DisabledValue, *c.Type)
+			}
+		}
+
+		if c.MaxSize != nil {
+			if *c.MaxSize <= 0 {
+				invalid("cache.maxSize", "must be positive, got %d", *c.MaxSize)
+			} else {
+				cfg.MaxCacheSize = *c.MaxSize
+			}
+		}
+
+		duration("cache.ttl", c.TTL, true, &cfg.CacheTTL)
+
+		// a time-to-live implies the ttl cache, unless another cache type is explicitly configured
+		if c.TTL != nil && c.Type == nil {
+			cfg.CacheType = cache.TTLValue
+		}
+
+		if c.ContextAware != nil {
+			cfg.ContextAwareCache = *c.ContextAware
+		}
+
+		if c.PersistencePath != nil {
+			cfg.CachePersistencePath = *c.PersistencePath
+		}
+
+		duration("cache.persistenceInterval", c.PersistenceInterval, false, &cfg.CachePersistenceInterval)
+	}
+
+	if r := file.Retry; r != nil {
+		if r.MaxAttempts != nil {
+			if *r.MaxAttempts < 0 {
+				invalid("retry.maxAttempts", "must not be negative, got %d", *r.MaxAttempts)
+			} else {
+				cfg.EventStreamConnectionMaxAttempts = *r.MaxAttempts
+			}
+		}
+
+		duration("retry.backoff", r.Backoff, true, &cfg.RetryBackoff)
+		duration("retry.backoffMax", r.BackoffMax, true, &cfg.RetryBackoffMax)
+
+		if cfg.RetryBackoffMax < cfg.RetryBackoff {
+			invalid("retry.backoffMax", "must not be less than retry.backoff %s, got %s",
+				cfg.RetryBackoff, cfg.RetryBackoffMax)
+		}
+
+		if r.Jitter != nil {
+			if *r.Jitter < 0 || *r.Jitter > 1 {
+				invalid("retry.jitter", "must be between 0 and 1, got %v", *r.Jitter)
+			} else {
+				cfg.RetryJitter = *r.Jitter
+			}
+		}
+
+		if r.Unlimited != nil {
+			cfg.RetryUnlimited = *r.Unlimited
+		}
+
+		duration("retry.gracePeriod", r.GracePeriod, false, &cfg.RetryGracePeriod)
+	}
+
+	if file.Selector != nil {
+		cfg.Selector = *file.Selector
+	}
+
+	if file.Selectors != nil {
+		for i, selector := range file.Selectors {
+			if selector == "" {
+				invalid(fmt.Sprintf("selectors[%d]", i), "must not be empty")
+			}
+		}
+
+		cfg.Selectors = file.Selectors
+	}
+
+	if file.OfflineFlagSourcePaths != nil {
+		for i, path := range file.OfflineFlagSourcePaths {
+			if path == "" {
+				invalid(fmt.Sprintf("offlineFlagSourcePaths[%d]", i), "must not be empty")
+			}
+		}
+
+		cfg.OfflineFlagSourcePaths = file.OfflineFlagSourcePaths
+	}
+
+	if file.SyncURL != nil {
+		cfg.SyncURL = *file.SyncURL
+	}
+
+	duration("syncPollInterval", file.SyncPollInterval, true, &cfg.SyncPollInterval)
+
+	if file.SnapshotPath != nil {
+		cfg.SnapshotPath = *file.SnapshotPath
+	}
+
+	if file.StrictValidation != nil {
+		cfg.StrictValidation = *file.StrictValidation
+	}
+
+	return errors.Join(errs...)
+}
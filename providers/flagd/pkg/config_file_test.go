@@ -0,0 +1,195 @@
+package flagd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/cache"
+	of "github.com/open-feature/go-sdk/openfeature"
+)
+
+func writeConfigFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestFromConfigFile(t *testing.T) {
+	yamlFile := writeConfigFile(t, "flagd.yaml", `
+resolver: in-process
+host: flagd.example.com
+port: 9090
+tls:
+  certPath: /certs/ca.pem
+cache:
+  type: ttl
+  maxSize: 200
+  ttl: 30s
+retry:
+  maxAttempts: 3
+  backoff: 500ms
+  backoffMax: 10s
+  jitter: 0.2
+  unlimited: true
+selector: flags.yaml
+selectors:
+  - base
+  - team
+offlineFlagSourcePaths:
+  - /etc/flags/defaults.json
+deadline: 2s
+`)
+
+	jsonFile := writeConfigFile(t, "flagd.json", `{
+	"resolver": "in-process",
+	"host": "flagd.example.com",
+	"port": 9090,
+	"tls": {"certPath": "/certs/ca.pem"},
+	"cache": {"type": "ttl", "maxSize": 200, "ttl": "30s"},
+	"retry": {"maxAttempts": 3, "backoff": "500ms", "backoffMax": "10s", "jitter": 0.2, "unlimited": true},
+	"selector": "flags.yaml",
+	"selectors": ["base", "team"],
+	"offlineFlagSourcePaths": ["/etc/flags/defaults.json"],
+	"deadline": "2s"
+}`)
+
+	for name, path := range map[string]string{"yaml": yamlFile, "json": jsonFile} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(flagdHostEnvironmentVariableName, "env.example.com")
+			t.Setenv(flagdSourceSelectorEnvironmentVariableName, "env-selector")
+
+			// options following the config file override its values
+			provider := NewProvider(FromConfigFile(path), WithSelector("option-selector"))
+			config := provider.providerConfiguration
+
+			if config.err != nil {
+				t.Fatalf("expected valid config file, got %v", config.err)
+			}
+
+			if config.Resolver != inProcess {
+				t.Errorf("expected resolver %s, got %s", inProcess, config.Resolver)
+			}
+
+			if config.Host != "flagd.example.com" {
+				t.Errorf("expected host of the config file to override the environment, got %s", config.Host)
+			}
+
+			if config.Port != 9090 {
+				t.Errorf("expected port 9090, got %d", config.Port)
+			}
+
+			if !config.TLSEnabled || config.CertificatePath != "/certs/ca.pem" {
+				t.Errorf("expected TLS with certificate /certs/ca.pem, got %v with %s",
+					config.TLSEnabled, config.CertificatePath)
+			}
+
+			if config.CacheType != cache.TTLValue || config.MaxCacheSize != 200 || config.CacheTTL != 30*time.Second {
+				t.Errorf("expected ttl cache of 200 entries for 30s, got %s cache of %d entries for %s",
+					config.CacheType, config.MaxCacheSize, config.CacheTTL)
+			}
+
+			if config.EventStreamConnectionMaxAttempts != 3 || config.RetryBackoff != 500*time.Millisecond ||
+				config.RetryBackoffMax != 10*time.Second || config.RetryJitter != 0.2 || !config.RetryUnlimited {
+				t.Errorf("unexpected retry configuration %d, %s, %s, %v, %v", config.EventStreamConnectionMaxAttempts,
+					config.RetryBackoff, config.RetryBackoffMax, config.RetryJitter, config.RetryUnlimited)
+			}
+
+			if config.Selector != "option-selector" {
+				t.Errorf("expected selector of the option to override the config file, got %s", config.Selector)
+			}
+
+			if !reflect.DeepEqual(config.Selectors, []string{"base", "team"}) {
+				t.Errorf("expected selectors of the config file, got %v", config.Selectors)
+			}
+
+			if !reflect.DeepEqual(config.OfflineFlagSourcePaths, []string{"/etc/flags/defaults.json"}) {
+				t.Errorf("expected offline flag source paths of the config file, got %v", config.OfflineFlagSourcePaths)
+			}
+
+			if config.Deadline != 2*time.Second {
+				t.Errorf("expected deadline 2s, got %s", config.Deadline)
+			}
+		})
+	}
+}
+
+func TestFromConfigFileErrors(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		"invalid values": {
+			name: "flagd.yaml",
+			content: `
+resolver: remote
+port: 70000
+cache:
+  ttl: soon
+retry:
+  jitter: 2
+`,
+			expected: []string{
+				`resolver: must be one of "rpc", "in-process", got "remote"`,
+				"port: must be between 1 and 65535, got 70000",
+				`cache.ttl: invalid duration "soon"`,
+				"retry.jitter: must be between 0 and 1, got 2",
+			},
+		},
+		"unknown key": {
+			name:     "flagd.json",
+			content:  `{"hots": "localhost"}`,
+			expected: []string{`unknown field "hots"`},
+		},
+		"wrong type": {
+			name:     "flagd.yaml",
+			content:  "port: default",
+			expected: []string{"cannot unmarshal"},
+		},
+		"unsupported extension": {
+			name:     "flagd.toml",
+			content:  `host = "localhost"`,
+			expected: []string{"unsupported file extension"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := writeConfigFile(t, test.name, test.content)
+
+			provider := NewProvider(FromConfigFile(path))
+
+			// invalid config files fail fast, without connecting
+			err := provider.Init(of.EvaluationContext{})
+			if !errors.Is(err, ErrInvalidConfigFile) {
+				t.Fatalf("expected config file error, got %v", err)
+			}
+
+			if provider.Status() != of.ErrorState {
+				t.Errorf("expected status %s, got %s", of.ErrorState, provider.Status())
+			}
+
+			for _, expected := range test.expected {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected error to contain %q, got %s", expected, err.Error())
+				}
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		provider := NewProvider(FromConfigFile(filepath.Join(t.TempDir(), "flagd.yaml")))
+
+		if err := provider.Init(of.EvaluationContext{}); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected missing file error, got %v", err)
+		}
+	})
+}
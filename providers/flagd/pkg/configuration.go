@@ -1,11 +1,17 @@
 package flagd
 
 import (
+	"errors"
 	"fmt"
 	"github.com/go-logr/logr"
 	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/cache"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/headers"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/retry"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/pkg/service/in_process"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type ResolverType string
@@ -13,6 +19,14 @@ type ResolverType string
 // Naming and defaults must comply with flagd environment variables
 const (
 	defaultMaxCacheSize          int  = 1000
+	defaultCacheTTL                   = time.Minute
+	defaultCachePersistInterval       = time.Minute
+	defaultDeadline                   = time.Duration(0)
+	defaultRetryBackoff               = retry.DefaultBaseDelay
+	defaultRetryBackoffMax            = retry.DefaultMaxDelay
+	defaultRetryJitter                = 0.0
+	defaultRetryGracePeriod           = 5 * time.Second
+	defaultSyncPollInterval           = 5 * time.Second
 	defaultPort                       = 8013
 	defaultMaxEventStreamRetries      = 5
 	defaultTLS                   bool = false
@@ -28,40 +42,96 @@ const (
 	flagdTLSEnvironmentVariableName                   = "FLAGD_TLS"
 	flagdSocketPathEnvironmentVariableName            = "FLAGD_SOCKET_PATH"
 	flagdServerCertPathEnvironmentVariableName        = "FLAGD_SERVER_CERT_PATH"
+	flagdClientCertPathEnvironmentVariableName        = "FLAGD_CLIENT_CERT_PATH"
+	flagdClientKeyPathEnvironmentVariableName         = "FLAGD_CLIENT_KEY_PATH"
+	flagdServerNameEnvironmentVariableName            = "FLAGD_SERVER_NAME"
 	flagdCacheEnvironmentVariableName                 = "FLAGD_CACHE"
 	flagdMaxCacheSizeEnvironmentVariableName          = "FLAGD_MAX_CACHE_SIZE"
+	flagdCacheTTLEnvironmentVariableName              = "FLAGD_CACHE_TTL"
 	flagdMaxEventStreamRetriesEnvironmentVariableName = "FLAGD_MAX_EVENT_STREAM_RETRIES"
 	flagdResolverEnvironmentVariableName              = "FLAGD_RESOLVER"
 	flagdSourceSelectorEnvironmentVariableName        = "FLAGD_SOURCE_SELECTOR"
 	flagdOfflinePathEnvironmentVariableName           = "FLAGD_OFFLINE_FLAG_SOURCE_PATH"
+	flagdDeadlineMsEnvironmentVariableName            = "FLAGD_DEADLINE_MS"
+	flagdRetryBackoffMsEnvironmentVariableName        = "FLAGD_RETRY_BACKOFF_MS"
+	flagdRetryBackoffMaxMsEnvironmentVariableName     = "FLAGD_RETRY_BACKOFF_MAX_MS"
+	flagdRetryJitterEnvironmentVariableName           = "FLAGD_RETRY_JITTER"
+	flagdRetryUnlimitedEnvironmentVariableName        = "FLAGD_RETRY_UNLIMITED"
+	flagdRetryGracePeriodEnvironmentVariableName      = "FLAGD_RETRY_GRACE_PERIOD"
+	flagdSyncURLEnvironmentVariableName               = "FLAGD_SYNC_URL"
+	flagdSyncPollIntervalMsEnvironmentVariableName    = "FLAGD_SYNC_POLL_INTERVAL_MS"
+	flagdSnapshotPathEnvironmentVariableName          = "FLAGD_SNAPSHOT_PATH"
+	flagdStrictValidationEnvironmentVariableName      = "FLAGD_STRICT_VALIDATION"
+	flagdProtocolEnvironmentVariableName              = "FLAGD_PROTOCOL"
+	flagdCompressionEnvironmentVariableName           = "FLAGD_COMPRESSION"
+	flagdH2CEnvironmentVariableName                   = "FLAGD_H2C"
+	flagdCachePersistencePathEnvironmentVariableName  = "FLAGD_CACHE_PERSISTENCE_PATH"
 )
 
 type providerConfiguration struct {
+	CachePersistenceInterval         time.Duration
+	CachePersistencePath             string
+	CacheTTL                         time.Duration
 	CacheType                        cache.Type
+	Compression                      string
 	CertificatePath                  string
+	ClientCertPath                   string
+	ClientKeyPath                    string
+	ContextAwareCache                bool
+	ContextEnrichments               []contextEnrichment
+	ContextPrecedence                ContextPrecedence
+	CustomEvaluators                 map[string]process.CustomEvaluator
+	Deadline                         time.Duration
 	EventStreamConnectionMaxAttempts int
+	H2C                              bool
+	Headers                          map[string]string
 	Host                             string
 	MaxCacheSize                     int
-	OfflineFlagSourcePath            string
+	Observer                         Observer
+	OfflineFlagSourcePaths           []string
 	OtelIntercept                    bool
 	Port                             uint16
+	Protocol                         Protocol
 	Resolver                         ResolverType
+	RetryBackoff                     time.Duration
+	RetryBackoffMax                  time.Duration
+	RetryGracePeriod                 time.Duration
+	RetryJitter                      float64
+	RetryUnlimited                   bool
 	Selector                         string
+	Selectors                        []string
+	ServerName                       string
+	Shadow                           *shadowConfiguration
+	SnapshotPath                     string
 	SocketPath                       string
+	StrictValidation                 bool
+	SyncPollInterval                 time.Duration
+	SyncURL                          string
 	TLSEnabled                       bool
+	TokenSource                      headers.TokenSource
 
 	log logr.Logger
+	// err is an error of the configuration, which fails the initialization
+	err error
 }
 
 func newDefaultConfiguration(log logr.Logger) *providerConfiguration {
 	p := &providerConfiguration{
+		CachePersistenceInterval:         defaultCachePersistInterval,
+		CacheTTL:                         defaultCacheTTL,
 		CacheType:                        defaultCache,
+		Deadline:                         defaultDeadline,
 		EventStreamConnectionMaxAttempts: defaultMaxEventStreamRetries,
 		Host:                             defaultHost,
 		log:                              log,
 		MaxCacheSize:                     defaultMaxCacheSize,
 		Port:                             defaultPort,
 		Resolver:                         defaultResolver,
+		RetryBackoff:                     defaultRetryBackoff,
+		RetryBackoffMax:                  defaultRetryBackoffMax,
+		RetryGracePeriod:                 defaultRetryGracePeriod,
+		RetryJitter:                      defaultRetryJitter,
+		SyncPollInterval:                 defaultSyncPollInterval,
 		TLSEnabled:                       defaultTLS,
 	}
 
@@ -100,6 +170,26 @@ func (cfg *providerConfiguration) updateFromEnvVar() {
 		cfg.CertificatePath = certificatePath
 	}
 
+	clientCertPath := os.Getenv(flagdClientCertPathEnvironmentVariableName)
+	clientKeyPath := os.Getenv(flagdClientKeyPathEnvironmentVariableName)
+	if clientCertPath != "" || clientKeyPath != "" {
+		if clientCertPath == "" || clientKeyPath == "" {
+			cfg.log.Error(errors.New("incomplete client certificate"),
+				fmt.Sprintf("invalid env config, %s and %s must be provided together, using no client certificate",
+					flagdClientCertPathEnvironmentVariableName, flagdClientKeyPathEnvironmentVariableName,
+				))
+		} else {
+			cfg.TLSEnabled = true
+			cfg.ClientCertPath = clientCertPath
+			cfg.ClientKeyPath = clientKeyPath
+		}
+	}
+
+	if serverName := os.Getenv(flagdServerNameEnvironmentVariableName); serverName != "" {
+		cfg.TLSEnabled = true
+		cfg.ServerName = serverName
+	}
+
 	if maxCacheSizeS := os.Getenv(flagdMaxCacheSizeEnvironmentVariableName); maxCacheSizeS != "" {
 		maxCacheSizeFromEnv, err := strconv.Atoi(maxCacheSizeS)
 		if err != nil {
@@ -118,6 +208,8 @@ func (cfg *providerConfiguration) updateFromEnvVar() {
 			cfg.CacheType = cache.LRUValue
 		case cache.InMemValue:
 			cfg.CacheType = cache.InMemValue
+		case cache.TTLValue:
+			cfg.CacheType = cache.TTLValue
 		case cache.DisabledValue:
 			cfg.CacheType = cache.DisabledValue
 		default:
@@ -126,6 +218,30 @@ func (cfg *providerConfiguration) updateFromEnvVar() {
 		}
 	}
 
+	if cacheTTLS := os.Getenv(flagdCacheTTLEnvironmentVariableName); cacheTTLS != "" {
+		cacheTTLMs, err := strconv.Atoi(cacheTTLS)
+		if err == nil && cacheTTLMs <= 0 {
+			err = fmt.Errorf("must be positive, got %d", cacheTTLMs)
+		}
+		if err != nil {
+			cfg.log.Error(err,
+				fmt.Sprintf("invalid env config for %s provided, using default value: %s",
+					flagdCacheTTLEnvironmentVariableName, defaultCacheTTL,
+				))
+		} else {
+			cfg.CacheTTL = time.Duration(cacheTTLMs) * time.Millisecond
+
+			// a time-to-live implies the ttl cache, unless another cache type is explicitly configured
+			if os.Getenv(flagdCacheEnvironmentVariableName) == "" {
+				cfg.CacheType = cache.TTLValue
+			}
+		}
+	}
+
+	if cachePersistencePath := os.Getenv(flagdCachePersistencePathEnvironmentVariableName); cachePersistencePath != "" {
+		cfg.CachePersistencePath = cachePersistencePath
+	}
+
 	if maxEventStreamRetriesS := os.Getenv(
 		flagdMaxEventStreamRetriesEnvironmentVariableName); maxEventStreamRetriesS != "" {
 
@@ -152,11 +268,175 @@ func (cfg *providerConfiguration) updateFromEnvVar() {
 	}
 
 	if offlinePath := os.Getenv(flagdOfflinePathEnvironmentVariableName); offlinePath != "" {
-		cfg.OfflineFlagSourcePath = offlinePath
+		cfg.OfflineFlagSourcePaths = nil
+		for _, path := range strings.Split(offlinePath, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				cfg.OfflineFlagSourcePaths = append(cfg.OfflineFlagSourcePaths, path)
+			}
+		}
 	}
 
 	if selector := os.Getenv(flagdSourceSelectorEnvironmentVariableName); selector != "" {
-		cfg.Selector = selector
+		var selectors []string
+		for _, s := range strings.Split(selector, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				selectors = append(selectors, s)
+			}
+		}
+
+		// multiple selectors are synced with a stream each
+		switch len(selectors) {
+		case 0:
+		case 1:
+			cfg.Selector = selectors[0]
+			cfg.Selectors = nil
+		default:
+			cfg.Selector = ""
+			cfg.Selectors = selectors
+		}
 	}
 
+	if syncURL := os.Getenv(flagdSyncURLEnvironmentVariableName); syncURL != "" {
+		cfg.SyncURL = syncURL
+	}
+
+	if syncPollIntervalMsS := os.Getenv(flagdSyncPollIntervalMsEnvironmentVariableName); syncPollIntervalMsS != "" {
+		syncPollIntervalMs, err := strconv.Atoi(syncPollIntervalMsS)
+		if err == nil && syncPollIntervalMs <= 0 {
+			err = fmt.Errorf("must be positive, got %d", syncPollIntervalMs)
+		}
+		if err != nil {
+			cfg.log.Error(err,
+				fmt.Sprintf("invalid env config for %s provided, using default value: %s",
+					flagdSyncPollIntervalMsEnvironmentVariableName, defaultSyncPollInterval,
+				))
+		} else {
+			cfg.SyncPollInterval = time.Duration(syncPollIntervalMs) * time.Millisecond
+		}
+	}
+
+	if snapshotPath := os.Getenv(flagdSnapshotPathEnvironmentVariableName); snapshotPath != "" {
+		cfg.SnapshotPath = snapshotPath
+	}
+
+	if retryBackoffMsS := os.Getenv(flagdRetryBackoffMsEnvironmentVariableName); retryBackoffMsS != "" {
+		retryBackoffMs, err := strconv.Atoi(retryBackoffMsS)
+		if err == nil && retryBackoffMs <= 0 {
+			err = fmt.Errorf("must be positive, got %d", retryBackoffMs)
+		}
+		if err != nil {
+			cfg.log.Error(err,
+				fmt.Sprintf("invalid env config for %s provided, using default value: %s",
+					flagdRetryBackoffMsEnvironmentVariableName, defaultRetryBackoff,
+				))
+		} else {
+			cfg.RetryBackoff = time.Duration(retryBackoffMs) * time.Millisecond
+		}
+	}
+
+	if retryBackoffMaxMsS := os.Getenv(flagdRetryBackoffMaxMsEnvironmentVariableName); retryBackoffMaxMsS != "" {
+		retryBackoffMaxMs, err := strconv.Atoi(retryBackoffMaxMsS)
+		if err == nil && retryBackoffMaxMs <= 0 {
+			err = fmt.Errorf("must be positive, got %d", retryBackoffMaxMs)
+		}
+		if err != nil {
+			cfg.log.Error(err,
+				fmt.Sprintf("invalid env config for %s provided, using default value: %s",
+					flagdRetryBackoffMaxMsEnvironmentVariableName, defaultRetryBackoffMax,
+				))
+		} else {
+			cfg.RetryBackoffMax = time.Duration(retryBackoffMaxMs) * time.Millisecond
+		}
+	}
+
+	if retryJitterS := os.Getenv(flagdRetryJitterEnvironmentVariableName); retryJitterS != "" {
+		retryJitter, err := strconv.ParseFloat(retryJitterS, 64)
+		if err == nil && (retryJitter < 0 || retryJitter > 1) {
+			err = fmt.Errorf("must be between 0 and 1, got %v", retryJitter)
+		}
+		if err != nil {
+			cfg.log.Error(err,
+				fmt.Sprintf("invalid env config for %s provided, using default value: %v",
+					flagdRetryJitterEnvironmentVariableName, defaultRetryJitter,
+				))
+		} else {
+			cfg.RetryJitter = retryJitter
+		}
+	}
+
+	if retryUnlimited := os.Getenv(flagdRetryUnlimitedEnvironmentVariableName); retryUnlimited != "" {
+		cfg.RetryUnlimited = retryUnlimited == "true"
+	}
+
+	if strictValidation := os.Getenv(flagdStrictValidationEnvironmentVariableName); strictValidation != "" {
+		cfg.StrictValidation = strictValidation == "true"
+	}
+
+	if protocol := os.Getenv(flagdProtocolEnvironmentVariableName); protocol != "" {
+		switch Protocol(protocol) {
+		case ProtocolConnect, ProtocolGRPC, ProtocolGRPCWeb:
+			cfg.Protocol = Protocol(protocol)
+		default:
+			cfg.log.Info(fmt.Sprintf("invalid env config for %s provided, using default value: %s",
+				flagdProtocolEnvironmentVariableName, ProtocolConnect))
+		}
+	}
+
+	if compression := os.Getenv(flagdCompressionEnvironmentVariableName); compression != "" {
+		switch compression {
+		case CompressionGzip:
+			cfg.Compression = compression
+		case "none":
+			cfg.Compression = ""
+		default:
+			cfg.log.Info(fmt.Sprintf("invalid env config for %s provided, using no compression",
+				flagdCompressionEnvironmentVariableName))
+		}
+	}
+
+	if h2c := os.Getenv(flagdH2CEnvironmentVariableName); h2c != "" {
+		cfg.H2C = h2c == "true"
+	}
+
+	if retryGracePeriodS := os.Getenv(flagdRetryGracePeriodEnvironmentVariableName); retryGracePeriodS != "" {
+		retryGracePeriod, err := strconv.Atoi(retryGracePeriodS)
+		if err == nil && retryGracePeriod < 0 {
+			err = fmt.Errorf("must not be negative, got %d", retryGracePeriod)
+		}
+		if err != nil {
+			cfg.log.Error(err,
+				fmt.Sprintf("invalid env config for %s provided, using default value: %s",
+					flagdRetryGracePeriodEnvironmentVariableName, defaultRetryGracePeriod,
+				))
+		} else {
+			cfg.RetryGracePeriod = time.Duration(retryGracePeriod) * time.Second
+		}
+	}
+
+	if deadlineMsS := os.Getenv(flagdDeadlineMsEnvironmentVariableName); deadlineMsS != "" {
+		deadlineMs, err := strconv.Atoi(deadlineMsS)
+		if err == nil && deadlineMs < 0 {
+			err = fmt.Errorf("must not be negative, got %d", deadlineMs)
+		}
+		if err != nil {
+			cfg.log.Error(err,
+				fmt.Sprintf("invalid env config for %s provided, using default value: %s",
+					flagdDeadlineMsEnvironmentVariableName, defaultDeadline,
+				))
+		} else {
+			cfg.Deadline = time.Duration(deadlineMs) * time.Millisecond
+		}
+	}
+
+}
+
+// retryPolicy derives the connection retry policy from the configuration
+func (cfg *providerConfiguration) retryPolicy() retry.Policy {
+	return retry.Policy{
+		BaseDelay:   cfg.RetryBackoff,
+		MaxDelay:    cfg.RetryBackoffMax,
+		Jitter:      cfg.RetryJitter,
+		MaxAttempts: cfg.EventStreamConnectionMaxAttempts,
+		Unlimited:   cfg.RetryUnlimited,
+	}
 }
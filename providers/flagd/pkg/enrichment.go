@@ -0,0 +1,59 @@
+package flagd
+
+import (
+	"context"
+
+	of "github.com/open-feature/go-sdk/openfeature"
+)
+
+// ContextEnricher returns attributes to add to the evaluation context of an evaluation
+type ContextEnricher func(ctx context.Context) map[string]interface{}
+
+// ContextPrecedence decides whether attributes of the caller's evaluation context or enriched attributes win if
+// their keys collide
+type ContextPrecedence int
+
+const (
+	// CallerContextPrecedence gives attributes of the caller's evaluation context precedence over enriched attributes
+	CallerContextPrecedence ContextPrecedence = iota
+	// EnricherContextPrecedence gives enriched attributes precedence over attributes of the caller's evaluation context
+	EnricherContextPrecedence
+)
+
+// contextEnrichment holds the static attributes and the dynamic enricher of a WithContextEnricher option
+type contextEnrichment struct {
+	static  map[string]interface{}
+	dynamic ContextEnricher
+}
+
+// enrich merges the enriched attributes into the evaluation context. Enrichments are applied in the order of their
+// options, where dynamic attributes of an enrichment override its static attributes. The evaluation context of the
+// caller is not modified
+func (p *Provider) enrich(ctx context.Context, evalCtx of.FlattenedContext) of.FlattenedContext {
+	enrichments := p.providerConfiguration.ContextEnrichments
+	if len(enrichments) == 0 {
+		return evalCtx
+	}
+
+	enriched := make(of.FlattenedContext, len(evalCtx))
+	for _, enrichment := range enrichments {
+		for k, v := range enrichment.static {
+			enriched[k] = v
+		}
+
+		if enrichment.dynamic != nil {
+			for k, v := range enrichment.dynamic(ctx) {
+				enriched[k] = v
+			}
+		}
+	}
+
+	for k, v := range evalCtx {
+		if _, ok := enriched[k]; ok && p.providerConfiguration.ContextPrecedence == EnricherContextPrecedence {
+			continue
+		}
+		enriched[k] = v
+	}
+
+	return enriched
+}
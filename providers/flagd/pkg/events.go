@@ -0,0 +1,18 @@
+package flagd
+
+import "github.com/open-feature/go-sdk-contrib/providers/flagd/internal/changes"
+
+// Metadata of openfeature.ProviderConfigChange events. The event metadata holds the changes of flags with the key
+// ChangesMetadataKey, as map[string]interface{} keyed by flag key. Each change is a map[string]interface{} holding the
+// change type with the key ChangeTypeKey, and the default variants before and after the change with the keys
+// OldDefaultVariantKey and NewDefaultVariantKey, if known
+const (
+	ChangesMetadataKey   = changes.MetadataKey
+	ChangeTypeKey        = changes.TypeKey
+	OldDefaultVariantKey = changes.OldDefaultVariantKey
+	NewDefaultVariantKey = changes.NewDefaultVariantKey
+
+	FlagAdded   = changes.Added
+	FlagUpdated = changes.Updated
+	FlagRemoved = changes.Removed
+)
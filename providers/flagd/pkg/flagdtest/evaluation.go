@@ -0,0 +1,219 @@
+package flagdtest
+
+import (
+	"context"
+	"fmt"
+
+	"buf.build/gen/go/open-feature/flagd/connectrpc/go/flagd/evaluation/v1/evaluationv1connect"
+	evalV1 "buf.build/gen/go/open-feature/flagd/protocolbuffers/go/flagd/evaluation/v1"
+	"connectrpc.com/connect"
+	"github.com/open-feature/flagd/core/pkg/model"
+	"github.com/open-feature/flagd/core/pkg/service"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// evaluationService implements the flag evaluation service of flagd
+type evaluationService struct {
+	evaluationv1connect.UnimplementedServiceHandler
+
+	server *Server
+}
+
+func (e *evaluationService) ResolveBoolean(ctx context.Context, req *connect.Request[evalV1.ResolveBooleanRequest]) (
+	*connect.Response[evalV1.ResolveBooleanResponse], error) {
+	if err := e.server.err(); err != nil {
+		return nil, err
+	}
+
+	value, variant, reason, metadata, err := e.server.evaluator.ResolveBooleanValue(
+		ctx, "", req.Msg.GetFlagKey(), req.Msg.GetContext().AsMap())
+	if err != nil {
+		return nil, errFormat(err)
+	}
+
+	metadataStruct, err := structpb.NewStruct(metadata)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeDataLoss, err)
+	}
+
+	return connect.NewResponse(&evalV1.ResolveBooleanResponse{
+		Value: value, Variant: variant, Reason: reason, Metadata: metadataStruct,
+	}), nil
+}
+
+func (e *evaluationService) ResolveString(ctx context.Context, req *connect.Request[evalV1.ResolveStringRequest]) (
+	*connect.Response[evalV1.ResolveStringResponse], error) {
+	if err := e.server.err(); err != nil {
+		return nil, err
+	}
+
+	value, variant, reason, metadata, err := e.server.evaluator.ResolveStringValue(
+		ctx, "", req.Msg.GetFlagKey(), req.Msg.GetContext().AsMap())
+	if err != nil {
+		return nil, errFormat(err)
+	}
+
+	metadataStruct, err := structpb.NewStruct(metadata)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeDataLoss, err)
+	}
+
+	return connect.NewResponse(&evalV1.ResolveStringResponse{
+		Value: value, Variant: variant, Reason: reason, Metadata: metadataStruct,
+	}), nil
+}
+
+func (e *evaluationService) ResolveInt(ctx context.Context, req *connect.Request[evalV1.ResolveIntRequest]) (
+	*connect.Response[evalV1.ResolveIntResponse], error) {
+	if err := e.server.err(); err != nil {
+		return nil, err
+	}
+
+	value, variant, reason, metadata, err := e.server.evaluator.ResolveIntValue(
+		ctx, "", req.Msg.GetFlagKey(), req.Msg.GetContext().AsMap())
+	if err != nil {
+		return nil, errFormat(err)
+	}
+
+	metadataStruct, err := structpb.NewStruct(metadata)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeDataLoss, err)
+	}
+
+	return connect.NewResponse(&evalV1.ResolveIntResponse{
+		Value: value, Variant: variant, Reason: reason, Metadata: metadataStruct,
+	}), nil
+}
+
+func (e *evaluationService) ResolveFloat(ctx context.Context, req *connect.Request[evalV1.ResolveFloatRequest]) (
+	*connect.Response[evalV1.ResolveFloatResponse], error) {
+	if err := e.server.err(); err != nil {
+		return nil, err
+	}
+
+	value, variant, reason, metadata, err := e.server.evaluator.ResolveFloatValue(
+		ctx, "", req.Msg.GetFlagKey(), req.Msg.GetContext().AsMap())
+	if err != nil {
+		return nil, errFormat(err)
+	}
+
+	metadataStruct, err := structpb.NewStruct(metadata)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeDataLoss, err)
+	}
+
+	return connect.NewResponse(&evalV1.ResolveFloatResponse{
+		Value: value, Variant: variant, Reason: reason, Metadata: metadataStruct,
+	}), nil
+}
+
+func (e *evaluationService) ResolveObject(ctx context.Context, req *connect.Request[evalV1.ResolveObjectRequest]) (
+	*connect.Response[evalV1.ResolveObjectResponse], error) {
+	if err := e.server.err(); err != nil {
+		return nil, err
+	}
+
+	value, variant, reason, metadata, err := e.server.evaluator.ResolveObjectValue(
+		ctx, "", req.Msg.GetFlagKey(), req.Msg.GetContext().AsMap())
+	if err != nil {
+		return nil, errFormat(err)
+	}
+
+	valueStruct, err := structpb.NewStruct(value)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeDataLoss, err)
+	}
+
+	metadataStruct, err := structpb.NewStruct(metadata)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeDataLoss, err)
+	}
+
+	return connect.NewResponse(&evalV1.ResolveObjectResponse{
+		Value: valueStruct, Variant: variant, Reason: reason, Metadata: metadataStruct,
+	}), nil
+}
+
+func (e *evaluationService) ResolveAll(ctx context.Context, req *connect.Request[evalV1.ResolveAllRequest]) (
+	*connect.Response[evalV1.ResolveAllResponse], error) {
+	if err := e.server.err(); err != nil {
+		return nil, err
+	}
+
+	res := &evalV1.ResolveAllResponse{Flags: map[string]*evalV1.AnyFlag{}}
+	for _, resolved := range e.server.evaluator.ResolveAllValues(ctx, "", req.Msg.GetContext().AsMap()) {
+		if resolved.Error != nil {
+			continue
+		}
+
+		flag := &evalV1.AnyFlag{Reason: resolved.Reason, Variant: resolved.Variant}
+		switch value := resolved.Value.(type) {
+		case bool:
+			flag.Value = &evalV1.AnyFlag_BoolValue{BoolValue: value}
+		case string:
+			flag.Value = &evalV1.AnyFlag_StringValue{StringValue: value}
+		case float64:
+			flag.Value = &evalV1.AnyFlag_DoubleValue{DoubleValue: value}
+		case map[string]interface{}:
+			valueStruct, err := structpb.NewStruct(value)
+			if err != nil {
+				return nil, connect.NewError(connect.CodeDataLoss, err)
+			}
+			flag.Value = &evalV1.AnyFlag_ObjectValue{ObjectValue: valueStruct}
+		default:
+			continue
+		}
+
+		res.Flags[resolved.FlagKey] = flag
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+// EventStream signals readiness and sends a configuration change event for each change of the flags, until the
+// stream is dropped
+func (e *evaluationService) EventStream(ctx context.Context, _ *connect.Request[evalV1.EventStreamRequest],
+	stream *connect.ServerStream[evalV1.EventStreamResponse]) error {
+	sub, _, err := e.server.subscribe()
+	if err != nil {
+		return err
+	}
+	defer e.server.unsubscribe(sub)
+
+	if err := stream.Send(&evalV1.EventStreamResponse{Type: string(service.ProviderReady)}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case changes := <-sub.changes:
+			data, err := structpb.NewStruct(map[string]interface{}{"flags": changes})
+			if err != nil {
+				return connect.NewError(connect.CodeDataLoss, err)
+			}
+
+			err = stream.Send(&evalV1.EventStreamResponse{Type: string(service.ConfigurationChange), Data: data})
+			if err != nil {
+				return err
+			}
+		case <-sub.drop:
+			return connect.NewError(connect.CodeUnavailable, fmt.Errorf("stream dropped"))
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// errFormat maps evaluation errors to the error codes of flagd
+func errFormat(err error) error {
+	switch err.Error() {
+	case model.FlagNotFoundErrorCode, model.FlagDisabledErrorCode:
+		return connect.NewError(connect.CodeNotFound, err)
+	case model.TypeMismatchErrorCode:
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	case model.ParseErrorCode:
+		return connect.NewError(connect.CodeDataLoss, err)
+	}
+
+	return connect.NewError(connect.CodeUnknown, err)
+}
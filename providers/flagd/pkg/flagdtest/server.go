@@ -0,0 +1,248 @@
+// Package flagdtest provides an in-process fake of flagd for tests. It serves the flag evaluation service with the
+// connect protocol and the flag sync service with gRPC, evaluating flag configurations supplied by the test with the
+// evaluator of flagd.
+package flagdtest
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"buf.build/gen/go/open-feature/flagd/connectrpc/go/flagd/evaluation/v1/evaluationv1connect"
+	"buf.build/gen/go/open-feature/flagd/grpc/go/flagd/sync/v1/syncv1grpc"
+	"connectrpc.com/connect"
+	"github.com/open-feature/flagd/core/pkg/evaluator"
+	flagdLogger "github.com/open-feature/flagd/core/pkg/logger"
+	"github.com/open-feature/flagd/core/pkg/store"
+	flagdSync "github.com/open-feature/flagd/core/pkg/sync"
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+)
+
+// source is the source of the flags of the server, as reported in configuration change events
+const source = "flagdtest"
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithUnixSockets serves the evaluation service and the sync service on unix sockets at the given paths, instead of
+// random TCP ports
+func WithUnixSockets(evaluationSocketPath string, syncSocketPath string) Option {
+	return func(s *Server) {
+		s.evaluationSocketPath = evaluationSocketPath
+		s.syncSocketPath = syncSocketPath
+	}
+}
+
+// Server is an in-process fake of flagd, serving the flag evaluation service and the flag sync service.
+// Flags are evaluated with the evaluator of flagd, hence targeting rules behave as with flagd
+type Server struct {
+	evaluationSocketPath string
+	syncSocketPath       string
+
+	evaluationListener net.Listener
+	syncListener       net.Listener
+	httpServer         *http.Server
+	grpcServer         *grpc.Server
+
+	evaluator *evaluator.JSON
+
+	mtx         sync.RWMutex
+	flags       string
+	injectedErr *connect.Error
+	// subscribers receive flag changes, until their stream ends
+	subscribers map[*subscriber]struct{}
+}
+
+// subscriber is an open event or sync stream
+type subscriber struct {
+	changes chan map[string]interface{}
+	drop    chan struct{}
+}
+
+// New starts a server serving the flag configuration, a JSON document of the flagd flag definition schema
+func New(flags string, opts ...Option) (*Server, error) {
+	s := &Server{
+		evaluator:   evaluator.NewJSON(flagdLogger.NewLogger(zap.NewNop(), false), store.NewFlags()),
+		subscribers: map[*subscriber]struct{}{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := s.SetFlags(flags); err != nil {
+		return nil, err
+	}
+
+	var err error
+	s.evaluationListener, err = listen(s.evaluationSocketPath)
+	if err != nil {
+		return nil, fmt.Errorf("error listening for the evaluation service: %w", err)
+	}
+
+	s.syncListener, err = listen(s.syncSocketPath)
+	if err != nil {
+		_ = s.evaluationListener.Close()
+		return nil, fmt.Errorf("error listening for the sync service: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(evaluationv1connect.NewServiceHandler(&evaluationService{server: s}))
+	s.httpServer = &http.Server{Handler: h2c.NewHandler(mux, &http2.Server{})}
+
+	s.grpcServer = grpc.NewServer()
+	syncv1grpc.RegisterFlagSyncServiceServer(s.grpcServer, &syncService{server: s})
+
+	go func() {
+		_ = s.httpServer.Serve(s.evaluationListener)
+	}()
+
+	go func() {
+		_ = s.grpcServer.Serve(s.syncListener)
+	}()
+
+	return s, nil
+}
+
+func listen(socketPath string) (net.Listener, error) {
+	if socketPath != "" {
+		return net.Listen("unix", socketPath)
+	}
+
+	return net.Listen("tcp", "localhost:0")
+}
+
+// Host is the host of the TCP ports of the server
+func (s *Server) Host() string {
+	return "localhost"
+}
+
+// EvaluationPort is the TCP port of the evaluation service, or zero if it is served on a unix socket
+func (s *Server) EvaluationPort() uint16 {
+	return port(s.evaluationListener)
+}
+
+// SyncPort is the TCP port of the sync service, or zero if it is served on a unix socket
+func (s *Server) SyncPort() uint16 {
+	return port(s.syncListener)
+}
+
+func port(listener net.Listener) uint16 {
+	if addr, ok := listener.Addr().(*net.TCPAddr); ok {
+		return uint16(addr.Port)
+	}
+
+	return 0
+}
+
+// SetFlags replaces the flag configuration. Open event streams receive a configuration change event with the changed
+// flags, and open sync streams receive the flag configuration
+func (s *Server) SetFlags(flags string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	changes, _, err := s.evaluator.SetState(flagdSync.DataSync{FlagData: flags, Source: source, Type: flagdSync.ALL})
+	if err != nil {
+		return fmt.Errorf("invalid flag configuration: %w", err)
+	}
+	s.flags = flags
+
+	for sub := range s.subscribers {
+		// a subscriber which did not consume previous changes receives them along with the latest ones
+		merged := make(map[string]interface{}, len(changes))
+		select {
+		case pending := <-sub.changes:
+			for key, change := range pending {
+				merged[key] = change
+			}
+		default:
+		}
+
+		for key, change := range changes {
+			merged[key] = change
+		}
+		sub.changes <- merged
+	}
+
+	return nil
+}
+
+// DropStreams ends all open event and sync streams with an unavailable error, as if the connection to flagd was lost.
+// Clients may reconnect right away
+func (s *Server) DropStreams() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for sub := range s.subscribers {
+		close(sub.drop)
+		delete(s.subscribers, sub)
+	}
+}
+
+// InjectError fails all calls, including new streams, with the error until ClearError is called. Codes apply to both
+// the connect protocol and gRPC
+func (s *Server) InjectError(code connect.Code, message string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.injectedErr = connect.NewError(code, errors.New(message))
+}
+
+// ClearError stops failing calls with an injected error
+func (s *Server) ClearError() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.injectedErr = nil
+}
+
+// Close stops the server, ending all streams
+func (s *Server) Close() {
+	s.DropStreams()
+	s.grpcServer.Stop()
+	_ = s.httpServer.Close()
+}
+
+// err returns the injected error, if any
+func (s *Server) err() *connect.Error {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	return s.injectedErr
+}
+
+// subscribe registers a subscriber for flag changes, unless an error is injected. The flag configuration at the time
+// of subscription is returned
+func (s *Server) subscribe() (*subscriber, string, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.injectedErr != nil {
+		return nil, "", s.injectedErr
+	}
+
+	sub := &subscriber{changes: make(chan map[string]interface{}, 1), drop: make(chan struct{})}
+	s.subscribers[sub] = struct{}{}
+
+	return sub, s.flags, nil
+}
+
+func (s *Server) unsubscribe(sub *subscriber) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	delete(s.subscribers, sub)
+}
+
+// currentFlags returns the flag configuration
+func (s *Server) currentFlags() string {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	return s.flags
+}
@@ -0,0 +1,44 @@
+package flagdtest
+
+import (
+	"fmt"
+	"testing"
+)
+
+func flagConfiguration(variantA string, variantB string) string {
+	return fmt.Sprintf(`{"flags": {
+		"flag-a": {"state": "ENABLED", "variants": {"on": true, "off": false}, "defaultVariant": %q},
+		"flag-b": {"state": "ENABLED", "variants": {"on": true, "off": false}, "defaultVariant": %q}
+	}}`, variantA, variantB)
+}
+
+func TestSetFlagsMergesPendingChanges(t *testing.T) {
+	server, err := New(flagConfiguration("on", "on"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	// given - a subscriber which does not consume changes
+	sub, _, err := server.subscribe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.unsubscribe(sub)
+
+	// when - flags change one after another
+	if err := server.SetFlags(flagConfiguration("off", "on")); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.SetFlags(flagConfiguration("off", "off")); err != nil {
+		t.Fatal(err)
+	}
+
+	// then - the changes of both are received
+	changes := <-sub.changes
+	for _, key := range []string{"flag-a", "flag-b"} {
+		if _, ok := changes[key]; !ok {
+			t.Errorf("expected change of %s, got %v", key, changes)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package flagdtest_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	flagd "github.com/open-feature/go-sdk-contrib/providers/flagd/pkg"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/pkg/flagdtest"
+	of "github.com/open-feature/go-sdk/openfeature"
+)
+
+const flagsV1 = `{
+  "flags": {
+    "myBoolFlag": {
+      "state": "ENABLED",
+      "variants": {"on": true, "off": false},
+      "defaultVariant": "on"
+    },
+    "myStringFlag": {
+      "state": "ENABLED",
+      "variants": {"a": "val-a", "b": "val-b"},
+      "defaultVariant": "a",
+      "targeting": {"if": [{"==": [{"var": "user"}, "beta"]}, "b", null]}
+    }
+  }
+}`
+
+const flagsV2 = `{
+  "flags": {
+    "myBoolFlag": {
+      "state": "ENABLED",
+      "variants": {"on": true, "off": false},
+      "defaultVariant": "off"
+    },
+    "myStringFlag": {
+      "state": "ENABLED",
+      "variants": {"a": "val-a", "b": "val-b"},
+      "defaultVariant": "a",
+      "targeting": {"if": [{"==": [{"var": "user"}, "beta"]}, "b", null]}
+    }
+  }
+}`
+
+func TestServer(t *testing.T) {
+	tests := []struct {
+		name    string
+		options func(server *flagdtest.Server) []flagd.ProviderOption
+	}{
+		{
+			name: "rpc resolver",
+			options: func(server *flagdtest.Server) []flagd.ProviderOption {
+				return []flagd.ProviderOption{
+					flagd.WithRPCResolver(),
+					flagd.WithHost(server.Host()),
+					flagd.WithPort(server.EvaluationPort()),
+				}
+			},
+		},
+		{
+			name: "in-process resolver",
+			options: func(server *flagdtest.Server) []flagd.ProviderOption {
+				return []flagd.ProviderOption{
+					flagd.WithInProcessResolver(),
+					flagd.WithHost(server.Host()),
+					flagd.WithPort(server.SyncPort()),
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newServer(t)
+
+			options := append(test.options(server), flagd.WithRetryBackoff(10*time.Millisecond, 50*time.Millisecond))
+			provider := flagd.NewProvider(options...)
+			if err := provider.Init(of.EvaluationContext{}); err != nil {
+				t.Fatalf("error initializing the provider: %v", err)
+			}
+			t.Cleanup(provider.Shutdown)
+
+			boolResult := provider.BooleanEvaluation(context.Background(), "myBoolFlag", false, nil)
+			if !boolResult.Value || boolResult.Variant != "on" {
+				t.Errorf("expected variant on, got %+v", boolResult)
+			}
+
+			stringResult := provider.StringEvaluation(context.Background(), "myStringFlag", "",
+				map[string]interface{}{"user": "beta"})
+			if stringResult.Value != "val-b" || stringResult.Reason != of.TargetingMatchReason {
+				t.Errorf("expected targeting match of variant b, got %+v", stringResult)
+			}
+
+			missing := provider.BooleanEvaluation(context.Background(), "missingFlag", false, nil)
+			if !isFlagNotFound(missing.ResolutionError) {
+				t.Errorf("expected flag not found error, got %v", missing.ResolutionError)
+			}
+
+			// changes are pushed to the provider
+			if err := server.SetFlags(flagsV2); err != nil {
+				t.Fatalf("error setting flags: %v", err)
+			}
+			awaitVariant(t, provider, "off")
+
+			// dropped streams are reconnected, and changes are pushed again
+			server.DropStreams()
+			if err := server.SetFlags(flagsV1); err != nil {
+				t.Fatalf("error setting flags: %v", err)
+			}
+			awaitVariant(t, provider, "on")
+		})
+	}
+}
+
+func TestServerInjectError(t *testing.T) {
+	server := newServer(t)
+
+	provider := flagd.NewProvider(
+		flagd.WithRPCResolver(),
+		flagd.WithoutCache(),
+		flagd.WithHost(server.Host()),
+		flagd.WithPort(server.EvaluationPort()),
+	)
+	if err := provider.Init(of.EvaluationContext{}); err != nil {
+		t.Fatalf("error initializing the provider: %v", err)
+	}
+	t.Cleanup(provider.Shutdown)
+
+	server.InjectError(connect.CodeNotFound, "injected")
+
+	result := provider.BooleanEvaluation(context.Background(), "myBoolFlag", false, nil)
+	if !isFlagNotFound(result.ResolutionError) {
+		t.Errorf("expected the injected flag not found error, got %v", result.ResolutionError)
+	}
+
+	server.ClearError()
+
+	result = provider.BooleanEvaluation(context.Background(), "myBoolFlag", false, nil)
+	if !result.Value || result.Error() != nil {
+		t.Errorf("expected the flag to resolve after clearing the error, got %+v", result)
+	}
+}
+
+func TestServerUnixSockets(t *testing.T) {
+	dir := t.TempDir()
+	server, err := flagdtest.New(flagsV1, flagdtest.WithUnixSockets(
+		filepath.Join(dir, "evaluation.sock"), filepath.Join(dir, "sync.sock")))
+	if err != nil {
+		t.Fatalf("error starting the server: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	if server.EvaluationPort() != 0 || server.SyncPort() != 0 {
+		t.Errorf("expected no TCP ports, got %d and %d", server.EvaluationPort(), server.SyncPort())
+	}
+
+	provider := flagd.NewProvider(
+		flagd.WithRPCResolver(),
+		flagd.WithoutCache(),
+		flagd.WithSocketPath(filepath.Join(dir, "evaluation.sock")),
+	)
+	if err := provider.Init(of.EvaluationContext{}); err != nil {
+		t.Fatalf("error initializing the provider: %v", err)
+	}
+	t.Cleanup(provider.Shutdown)
+
+	result := provider.BooleanEvaluation(context.Background(), "myBoolFlag", false, nil)
+	if !result.Value {
+		t.Errorf("expected variant on, got %+v", result)
+	}
+}
+
+func TestServerInvalidFlags(t *testing.T) {
+	if _, err := flagdtest.New("{"); err == nil {
+		t.Error("expected an error for an invalid flag configuration")
+	}
+
+	server := newServer(t)
+	if err := server.SetFlags("{"); err == nil {
+		t.Error("expected an error for an invalid flag configuration")
+	}
+}
+
+func newServer(t *testing.T) *flagdtest.Server {
+	t.Helper()
+
+	server, err := flagdtest.New(flagsV1)
+	if err != nil {
+		t.Fatalf("error starting the server: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func isFlagNotFound(err of.ResolutionError) bool {
+	return strings.HasPrefix(err.Error(), string(of.FlagNotFoundCode))
+}
+
+// awaitVariant polls the evaluation of myBoolFlag until it resolves to the variant
+func awaitVariant(t *testing.T, provider *flagd.Provider, variant string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		result := provider.BooleanEvaluation(context.Background(), "myBoolFlag", false, nil)
+		if result.Variant == variant {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for variant %s, got %+v", variant, result)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
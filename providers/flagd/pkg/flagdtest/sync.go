@@ -0,0 +1,74 @@
+package flagdtest
+
+import (
+	"context"
+	"errors"
+
+	"buf.build/gen/go/open-feature/flagd/grpc/go/flagd/sync/v1/syncv1grpc"
+	syncV1 "buf.build/gen/go/open-feature/flagd/protocolbuffers/go/flagd/sync/v1"
+	"connectrpc.com/connect"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// syncService implements the flag sync service of flagd. Selectors are ignored, all flags are synced
+type syncService struct {
+	syncv1grpc.UnimplementedFlagSyncServiceServer
+
+	server *Server
+}
+
+// SyncFlags sends the flag configuration, and again for each change of the flags, until the stream is dropped
+func (s *syncService) SyncFlags(_ *syncV1.SyncFlagsRequest, stream syncv1grpc.FlagSyncService_SyncFlagsServer) error {
+	sub, flags, err := s.server.subscribe()
+	if err != nil {
+		return grpcError(err)
+	}
+	defer s.server.unsubscribe(sub)
+
+	if err := stream.Send(&syncV1.SyncFlagsResponse{FlagConfiguration: flags}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-sub.changes:
+			err := stream.Send(&syncV1.SyncFlagsResponse{FlagConfiguration: s.server.currentFlags()})
+			if err != nil {
+				return err
+			}
+		case <-sub.drop:
+			return status.Error(codes.Unavailable, "stream dropped")
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (s *syncService) FetchAllFlags(context.Context, *syncV1.FetchAllFlagsRequest) (
+	*syncV1.FetchAllFlagsResponse, error) {
+	if err := s.server.err(); err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &syncV1.FetchAllFlagsResponse{FlagConfiguration: s.server.currentFlags()}, nil
+}
+
+func (s *syncService) GetMetadata(context.Context, *syncV1.GetMetadataRequest) (*syncV1.GetMetadataResponse, error) {
+	if err := s.server.err(); err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &syncV1.GetMetadataResponse{Metadata: &structpb.Struct{}}, nil
+}
+
+// grpcError converts an injected error to a gRPC status error of the same code
+func grpcError(err error) error {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return status.Error(codes.Code(connectErr.Code()), connectErr.Message())
+	}
+
+	return err
+}
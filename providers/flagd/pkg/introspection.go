@@ -0,0 +1,42 @@
+package flagd
+
+import (
+	"errors"
+
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/pkg/service/in_process"
+)
+
+// FlagDefinition is the definition of a flag loaded by the in-process resolver, including its source
+type FlagDefinition = process.FlagDefinition
+
+// ErrIntrospectionUnsupported is returned by introspection of providers with a resolver which does not load flags
+var ErrIntrospectionUnsupported = errors.New("flag introspection is only supported by the in-process resolver")
+
+// flagIntrospector is implemented by services loading flags
+type flagIntrospector interface {
+	Flags() []process.FlagDefinition
+	FlagDefinition(key string) (process.FlagDefinition, bool)
+}
+
+// Flags returns the definitions of all flags currently loaded by the in-process resolver, ordered by key.
+// ErrIntrospectionUnsupported is returned for the rpc resolver
+func (p *Provider) Flags() ([]FlagDefinition, error) {
+	introspector, ok := p.service.(flagIntrospector)
+	if !ok {
+		return nil, ErrIntrospectionUnsupported
+	}
+
+	return introspector.Flags(), nil
+}
+
+// FlagDefinition returns the definition of the flag with the key currently loaded by the in-process resolver, and
+// whether the flag is loaded. ErrIntrospectionUnsupported is returned for the rpc resolver
+func (p *Provider) FlagDefinition(key string) (FlagDefinition, bool, error) {
+	introspector, ok := p.service.(flagIntrospector)
+	if !ok {
+		return FlagDefinition{}, false, ErrIntrospectionUnsupported
+	}
+
+	definition, found := introspector.FlagDefinition(key)
+	return definition, found, nil
+}
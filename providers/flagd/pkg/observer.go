@@ -0,0 +1,30 @@
+package flagd
+
+import (
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/observer"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Observer is notified about the cache, the connection and the flag syncs of the provider. Implementations must be
+// safe for concurrent use, and must not block as they are called on the evaluation and connection paths.
+// Cache notifications only apply to the rpc resolver
+type Observer = observer.Observer
+
+// NoopObserver is an Observer ignoring all notifications. Embed it to implement a subset of Observer
+type NoopObserver = observer.Noop
+
+// NewOtelObserver creates an Observer recording OpenTelemetry metrics with a meter of the provider. The global meter
+// provider is used if provider is nil
+func NewOtelObserver(provider metric.MeterProvider) (Observer, error) {
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+
+	o, err := observer.NewOtel(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
@@ -0,0 +1,18 @@
+package flagd
+
+import rpcService "github.com/open-feature/go-sdk-contrib/providers/flagd/pkg/service/rpc"
+
+// Protocol is the wire protocol of the rpc resolver
+type Protocol = rpcService.Protocol
+
+const (
+	// ProtocolConnect is the connect protocol, supported by flagd on its evaluation port
+	ProtocolConnect = rpcService.ProtocolConnect
+	// ProtocolGRPC is plain gRPC. Without TLS, it requires h2c
+	ProtocolGRPC = rpcService.ProtocolGRPC
+	// ProtocolGRPCWeb is gRPC-Web, as served by proxies for browsers
+	ProtocolGRPCWeb = rpcService.ProtocolGRPCWeb
+)
+
+// CompressionGzip compresses requests of the rpc resolver with gzip
+const CompressionGzip = rpcService.CompressionGzip
@@ -2,6 +2,7 @@ package flagd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/go-logr/logr"
 	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/cache"
@@ -9,7 +10,9 @@ import (
 	"github.com/open-feature/go-sdk-contrib/providers/flagd/pkg/service/in_process"
 	rpcService "github.com/open-feature/go-sdk-contrib/providers/flagd/pkg/service/rpc"
 	of "github.com/open-feature/go-sdk/openfeature"
+	"math"
 	"sync"
+	"time"
 )
 
 type Provider struct {
@@ -17,10 +20,13 @@ type Provider struct {
 	logger                logr.Logger
 	providerConfiguration *providerConfiguration
 	service               IService
+	shadow                *shadowEvaluation
 	status                of.State
 	mtx                   sync.RWMutex
 
 	eventStream chan of.Event
+	// stopEvents stops event handling, which runs from the first initialization until shutdown
+	stopEvents chan struct{}
 }
 
 func NewProvider(opts ...ProviderOption) *Provider {
@@ -42,38 +48,80 @@ func NewProvider(opts ...ProviderOption) *Provider {
 		opt(provider)
 	}
 
-	cacheService := cache.NewCacheService(
-		provider.providerConfiguration.CacheType,
-		provider.providerConfiguration.MaxCacheSize,
-		provider.logger)
+	provider.service = newService(provider.providerConfiguration, provider.logger)
 
+	if provider.providerConfiguration.Shadow != nil {
+		provider.shadow = newShadowEvaluation(provider.providerConfiguration, provider.logger)
+	}
+
+	return provider
+}
+
+// newService creates the service of the configured resolver
+func newService(cfg *providerConfiguration, log logr.Logger) IService {
 	var service IService
-	if provider.providerConfiguration.Resolver == rpc {
+	if cfg.Resolver == rpc {
+		// the cache is owned by the rpc service, which closes it on shutdown
+		cacheService := cache.NewCacheService(
+			cfg.CacheType,
+			cfg.MaxCacheSize,
+			cfg.CacheTTL,
+			log)
+
 		service = rpcService.NewService(
 			rpcService.Configuration{
-				Host:            provider.providerConfiguration.Host,
-				Port:            provider.providerConfiguration.Port,
-				CertificatePath: provider.providerConfiguration.CertificatePath,
-				SocketPath:      provider.providerConfiguration.SocketPath,
-				TLSEnabled:      provider.providerConfiguration.TLSEnabled,
-				OtelInterceptor: provider.providerConfiguration.OtelIntercept,
+				Host:              cfg.Host,
+				Port:              cfg.Port,
+				CertificatePath:   cfg.CertificatePath,
+				ClientCertPath:    cfg.ClientCertPath,
+				ClientKeyPath:     cfg.ClientKeyPath,
+				ServerName:        cfg.ServerName,
+				Headers:           cfg.Headers,
+				TokenSource:       cfg.TokenSource,
+				SocketPath:        cfg.SocketPath,
+				TLSEnabled:        cfg.TLSEnabled,
+				OtelInterceptor:   cfg.OtelIntercept,
+				ContextAwareCache: cfg.ContextAwareCache,
+				RetryGracePeriod:  cfg.RetryGracePeriod,
+				Observer:          cfg.Observer,
+				Protocol:          cfg.Protocol,
+				Compression:       cfg.Compression,
+				H2C:               cfg.H2C,
+				CallTimeout:       cfg.Deadline,
+
+				CachePersistencePath:     cfg.CachePersistencePath,
+				CachePersistenceInterval: cfg.CachePersistenceInterval,
 			},
 			cacheService,
-			provider.logger,
-			provider.providerConfiguration.EventStreamConnectionMaxAttempts)
+			log,
+			cfg.retryPolicy())
 	} else {
 		service = process.NewInProcessService(process.Configuration{
-			Host:              provider.providerConfiguration.Host,
-			Port:              provider.providerConfiguration.Port,
-			Selector:          provider.providerConfiguration.Selector,
-			TLSEnabled:        provider.providerConfiguration.TLSEnabled,
-			OfflineFlagSource: provider.providerConfiguration.OfflineFlagSourcePath,
+			Host:               cfg.Host,
+			Port:               cfg.Port,
+			Selector:           cfg.Selector,
+			Selectors:          cfg.Selectors,
+			TLSEnabled:         cfg.TLSEnabled,
+			CertificatePath:    cfg.CertificatePath,
+			ClientCertPath:     cfg.ClientCertPath,
+			ClientKeyPath:      cfg.ClientKeyPath,
+			ServerName:         cfg.ServerName,
+			Headers:            cfg.Headers,
+			TokenSource:        cfg.TokenSource,
+			SyncURL:            cfg.SyncURL,
+			SyncPollInterval:   cfg.SyncPollInterval,
+			OfflineFlagSources: cfg.OfflineFlagSourcePaths,
+			Logger:             log,
+			RetryPolicy:        cfg.retryPolicy(),
+			RetryGracePeriod:   cfg.RetryGracePeriod,
+			SnapshotPath:       cfg.SnapshotPath,
+			CustomEvaluators:   cfg.CustomEvaluators,
+			Observer:           cfg.Observer,
+			StrictValidation:   cfg.StrictValidation,
 		})
 	}
 
-	provider.service = service
-
-	return provider
+	return service
 }
 
 func (p *Provider) Init(_ of.EvaluationContext) error {
@@ -85,36 +133,84 @@ func (p *Provider) Init(_ of.EvaluationContext) error {
 		return nil
 	}
 
+	if p.providerConfiguration.err != nil {
+		p.status = of.ErrorState
+		return p.providerConfiguration.err
+	}
+
 	err := p.service.Init()
 	if err != nil {
 		return err
 	}
 
+	p.shadow.init()
+
+	// bound the wait for initialization with the deadline, if configured
+	var deadline <-chan time.Time
+	if p.providerConfiguration.Deadline > 0 {
+		timer := time.NewTimer(p.providerConfiguration.Deadline)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
 	// wait for initialization from the service
-	e := <-p.service.EventChannel()
-	if e.EventType != of.ProviderReady {
-		return fmt.Errorf("provider initialization failed: %s", e.ProviderEventDetails.Message)
+	var initErr error
+	select {
+	case e := <-p.service.EventChannel():
+		switch e.EventType {
+		case of.ProviderReady:
+			p.status = of.ReadyState
+		case of.ProviderStale:
+			// the service serves flags, but they may be outdated
+			p.status = of.StaleState
+		default:
+			p.status = of.ErrorState
+			initErr = fmt.Errorf("provider initialization failed: %s", e.ProviderEventDetails.Message)
+		}
+	case <-deadline:
+		initErr = fmt.Errorf("provider initialization did not complete within the deadline of %s, "+
+			"connection attempts continue in the background", p.providerConfiguration.Deadline)
 	}
 
-	p.status = of.ReadyState
 	p.initialized = true
 
-	// start event handling after the first ready event
-	go func() {
-		for {
-			event := <-p.service.EventChannel()
-			p.eventStream <- event
-			switch event.EventType {
-			case of.ProviderReady:
-			case of.ProviderConfigChange:
-				p.setStatus(of.ReadyState)
-			case of.ProviderError:
-				p.setStatus(of.ErrorState)
-			}
+	// start event handling after the initial event. Services keep connecting in the background after a failed
+	// initialization, hence the provider becomes ready with a later ready event
+	if p.stopEvents == nil {
+		p.stopEvents = make(chan struct{})
+		go p.handleEvents(p.stopEvents)
+	}
+
+	return initErr
+}
+
+// handleEvents updates the provider status based on service events and forwards them to the provider's event stream,
+// until stop is closed
+func (p *Provider) handleEvents(stop <-chan struct{}) {
+	for {
+		var event of.Event
+		select {
+		case event = <-p.service.EventChannel():
+		case <-stop:
+			return
+		}
+
+		// the status is updated first, hence it reflects the event once it is received
+		switch event.EventType {
+		case of.ProviderReady, of.ProviderConfigChange:
+			p.setStatus(of.ReadyState)
+		case of.ProviderStale:
+			p.setStatus(of.StaleState)
+		case of.ProviderError:
+			p.setStatus(of.ErrorState)
 		}
-	}()
 
-	return nil
+		select {
+		case p.eventStream <- event:
+		case <-stop:
+			return
+		}
+	}
 }
 
 func (p *Provider) Status() of.State {
@@ -128,7 +224,12 @@ func (p *Provider) Shutdown() {
 	defer p.mtx.Unlock()
 
 	p.initialized = false
+	if p.stopEvents != nil {
+		close(p.stopEvents)
+		p.stopEvents = nil
+	}
 	p.service.Shutdown()
+	p.shadow.shutdown()
 }
 
 func (p *Provider) EventChannel() <-chan of.Event {
@@ -150,31 +251,86 @@ func (p *Provider) Metadata() of.Metadata {
 func (p *Provider) BooleanEvaluation(
 	ctx context.Context, flagKey string, defaultValue bool, evalCtx of.FlattenedContext,
 ) of.BoolResolutionDetail {
-	return p.service.ResolveBoolean(ctx, flagKey, defaultValue, evalCtx)
+	evalCtx = p.enrich(ctx, evalCtx)
+	detail := p.service.ResolveBoolean(ctx, flagKey, defaultValue, evalCtx)
+
+	p.shadow.evaluate(ctx, flagKey, evalCtx, detail.Value, detail.ProviderResolutionDetail,
+		func(ctx context.Context, service IService) (interface{}, of.ProviderResolutionDetail) {
+			shadow := service.ResolveBoolean(ctx, flagKey, defaultValue, evalCtx)
+			return shadow.Value, shadow.ProviderResolutionDetail
+		})
+
+	return detail
 }
 
 func (p *Provider) StringEvaluation(
 	ctx context.Context, flagKey string, defaultValue string, evalCtx of.FlattenedContext,
 ) of.StringResolutionDetail {
-	return p.service.ResolveString(ctx, flagKey, defaultValue, evalCtx)
+	evalCtx = p.enrich(ctx, evalCtx)
+	detail := p.service.ResolveString(ctx, flagKey, defaultValue, evalCtx)
+
+	p.shadow.evaluate(ctx, flagKey, evalCtx, detail.Value, detail.ProviderResolutionDetail,
+		func(ctx context.Context, service IService) (interface{}, of.ProviderResolutionDetail) {
+			shadow := service.ResolveString(ctx, flagKey, defaultValue, evalCtx)
+			return shadow.Value, shadow.ProviderResolutionDetail
+		})
+
+	return detail
 }
 
 func (p *Provider) FloatEvaluation(
 	ctx context.Context, flagKey string, defaultValue float64, evalCtx of.FlattenedContext,
 ) of.FloatResolutionDetail {
-	return p.service.ResolveFloat(ctx, flagKey, defaultValue, evalCtx)
+	evalCtx = p.enrich(ctx, evalCtx)
+	detail := p.service.ResolveFloat(ctx, flagKey, defaultValue, evalCtx)
+
+	p.shadow.evaluate(ctx, flagKey, evalCtx, detail.Value, detail.ProviderResolutionDetail,
+		func(ctx context.Context, service IService) (interface{}, of.ProviderResolutionDetail) {
+			shadow := service.ResolveFloat(ctx, flagKey, defaultValue, evalCtx)
+			return shadow.Value, shadow.ProviderResolutionDetail
+		})
+
+	return detail
 }
 
 func (p *Provider) IntEvaluation(
 	ctx context.Context, flagKey string, defaultValue int64, evalCtx of.FlattenedContext,
 ) of.IntResolutionDetail {
-	return p.service.ResolveInt(ctx, flagKey, defaultValue, evalCtx)
+	evalCtx = p.enrich(ctx, evalCtx)
+	detail := p.service.ResolveInt(ctx, flagKey, defaultValue, evalCtx)
+
+	p.shadow.evaluate(ctx, flagKey, evalCtx, detail.Value, detail.ProviderResolutionDetail,
+		func(ctx context.Context, service IService) (interface{}, of.ProviderResolutionDetail) {
+			shadow := service.ResolveInt(ctx, flagKey, defaultValue, evalCtx)
+			return shadow.Value, shadow.ProviderResolutionDetail
+		})
+
+	return detail
 }
 
 func (p *Provider) ObjectEvaluation(
 	ctx context.Context, flagKey string, defaultValue interface{}, evalCtx of.FlattenedContext,
 ) of.InterfaceResolutionDetail {
-	return p.service.ResolveObject(ctx, flagKey, defaultValue, evalCtx)
+	evalCtx = p.enrich(ctx, evalCtx)
+	detail := p.service.ResolveObject(ctx, flagKey, defaultValue, evalCtx)
+
+	p.shadow.evaluate(ctx, flagKey, evalCtx, detail.Value, detail.ProviderResolutionDetail,
+		func(ctx context.Context, service IService) (interface{}, of.ProviderResolutionDetail) {
+			shadow := service.ResolveObject(ctx, flagKey, defaultValue, evalCtx)
+			return shadow.Value, shadow.ProviderResolutionDetail
+		})
+
+	return detail
+}
+
+// ResolveAll evaluates all flags for the given evaluation context in a single call, keyed by flag key.
+// Resolved values are typed by their flag definition, as bool, string, float64 or map[string]interface{}.
+// Failures to evaluate an individual flag are reported through the ResolutionError of its resolution detail.
+// Resolutions of the rpc resolver carry no flag metadata, as flagd does not provide it for bulk evaluations.
+func (p *Provider) ResolveAll(
+	ctx context.Context, evalCtx of.FlattenedContext,
+) (map[string]of.InterfaceResolutionDetail, error) {
+	return p.service.ResolveAll(ctx, p.enrich(ctx, evalCtx))
 }
 
 func (p *Provider) setStatus(status of.State) {
@@ -203,6 +359,46 @@ func WithCertificatePath(path string) ProviderOption {
 	}
 }
 
+// WithClientCertificate sets the client certificate and key presented to flagd for mutual TLS, and enables TLS.
+// Rotated certificate files are picked up with new connections
+func WithClientCertificate(certPath string, keyPath string) ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.ClientCertPath = certPath
+		p.providerConfiguration.ClientKeyPath = keyPath
+		p.providerConfiguration.TLSEnabled = true
+	}
+}
+
+// WithServerName overrides the server name used to verify the certificate of flagd, and enables TLS
+func WithServerName(serverName string) ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.ServerName = serverName
+		p.providerConfiguration.TLSEnabled = true
+	}
+}
+
+// WithHeaders adds headers to all requests made to flagd, including the event stream of the RPC resolver and the
+// sync stream of the in-process resolver. Use it to authenticate against a proxy placed in front of flagd
+func WithHeaders(headers map[string]string) ProviderOption {
+	return func(p *Provider) {
+		if p.providerConfiguration.Headers == nil {
+			p.providerConfiguration.Headers = make(map[string]string, len(headers))
+		}
+		for k, v := range headers {
+			p.providerConfiguration.Headers[k] = v
+		}
+	}
+}
+
+// WithTokenSource sets a callback providing the bearer token of requests made to flagd. The callback is invoked for
+// each request and stream, hence must cache and refresh tokens as needed. The token overrides an authorization header
+// set with WithHeaders
+func WithTokenSource(source func(ctx context.Context) (string, error)) ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.TokenSource = source
+	}
+}
+
 // WithPort specifies the port of the flagd server. Defaults to 8013
 func WithPort(port uint16) ProviderOption {
 	return func(p *Provider) {
@@ -243,6 +439,42 @@ func WithLRUCache(size int) ProviderOption {
 	}
 }
 
+// WithTTLCache applies an in memory cache where each entry expires once the provided time-to-live elapsed since it was
+// cached. This bounds the staleness of cached values, even if flag change events stop arriving. The provided size is
+// the limit of the number of cached values, once reached each new entry replaces the entry closest to its expiry.
+func WithTTLCache(size int, ttl time.Duration) ProviderOption {
+	return func(p *Provider) {
+		if size > 0 {
+			p.providerConfiguration.MaxCacheSize = size
+		}
+		if ttl > 0 {
+			p.providerConfiguration.CacheTTL = ttl
+		}
+		p.providerConfiguration.CacheType = cache.TTLValue
+	}
+}
+
+// WithContextAwareCache enables caching of targeted evaluations. Targeted resolutions are cached with a key derived
+// from the flag key and a hash of the flattened evaluation context, hence repeated evaluations with an equal context
+// are served from the cache. Cached entries are invalidated on flag configuration changes.
+// Flags with targeting rules depending on time (ex:- $flagd.timestamp) should not be used with this option.
+func WithContextAwareCache() ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.ContextAwareCache = true
+	}
+}
+
+// WithPersistentCache persists static resolutions of the cache to the file at path in the interval and at shutdown.
+// They are loaded at initialization and served with the reason rpc.ReasonPersisted, until the first configuration
+// change invalidates them. A zero interval only persists the cache at shutdown. Defaults to an interval of 1 minute.
+// This is only useful with RPC resolver type
+func WithPersistentCache(path string, interval time.Duration) ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.CachePersistencePath = path
+		p.providerConfiguration.CachePersistenceInterval = interval
+	}
+}
+
 // WithEventStreamConnectionMaxAttempts sets the maximum number of attempts to connect to flagd's event stream.
 // On successful connection the attempts are reset.
 func WithEventStreamConnectionMaxAttempts(i int) ProviderOption {
@@ -251,6 +483,57 @@ func WithEventStreamConnectionMaxAttempts(i int) ProviderOption {
 	}
 }
 
+// WithDeadline bounds the provider initialization. If the provider is not ready within the deadline, initialization
+// fails with an error while connection attempts continue in the background. The provider then becomes ready and emits
+// a ready event once the connection is established. A zero deadline waits for initialization without a bound.
+// The deadline also bounds evaluations of the rpc resolver, unless the context of an evaluation has a deadline
+func WithDeadline(deadline time.Duration) ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.Deadline = deadline
+	}
+}
+
+// WithRetryBackoff sets the backoff between connection attempts to flagd's event stream and to the sync source of
+// the in-process resolver. The delay starts at base and doubles with each failed attempt, up to max.
+// Defaults to 1 second base and 120 seconds max
+func WithRetryBackoff(base time.Duration, max time.Duration) ProviderOption {
+	return func(p *Provider) {
+		if base > 0 {
+			p.providerConfiguration.RetryBackoff = base
+		}
+		if max > 0 {
+			p.providerConfiguration.RetryBackoffMax = max
+		}
+	}
+}
+
+// WithRetryJitter sets the fraction [0, 1] of each connection retry delay which is randomized. Jitter spreads the
+// connection attempts of many clients reconnecting at once. Defaults to 0, no jitter
+func WithRetryJitter(jitter float64) ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.RetryJitter = jitter
+	}
+}
+
+// WithUnlimitedRetries keeps retrying connections once the attempts set by WithEventStreamConnectionMaxAttempts are
+// exhausted. The provider still reports the failed connection, but continues to connect with a backoff delay which
+// keeps doubling up to the maximum backoff delay. Once reconnected, the cache is re-enabled and the provider emits a
+// ready event
+func WithUnlimitedRetries() ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.RetryUnlimited = true
+	}
+}
+
+// WithRetryGracePeriod sets the period the provider stays stale once an established connection is lost. During the
+// grace period, the provider emits a stale event and keeps serving its last known flag values. If the connection is not
+// re-established within the period, the provider emits an error event and purges the cache. Defaults to 5 seconds
+func WithRetryGracePeriod(period time.Duration) ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.RetryGracePeriod = period
+	}
+}
+
 // WithLogger sets the logger used by the provider.
 func WithLogger(l logr.Logger) ProviderOption {
 	return func(p *Provider) {
@@ -290,8 +573,152 @@ func WithInProcessResolver() ProviderOption {
 // WithOfflineFilePath file path to obtain flags to run provider in offline mode with in-process evaluations.
 // This is only useful with inProcess resolver type
 func WithOfflineFilePath(path string) ProviderOption {
+	return WithOfflineFilePaths(path)
+}
+
+// WithOfflineFilePaths files, directories or glob patterns to obtain flags to run provider in offline mode with
+// in-process evaluations. Each file is watched independently. If multiple files define a flag, the flag of the file
+// given last takes precedence. Files of a directory or glob pattern are ordered by name.
+// This is only useful with inProcess resolver type
+func WithOfflineFilePaths(paths ...string) ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.OfflineFlagSourcePaths = paths
+	}
+}
+
+// WithSyncURL sets the URL of a flag configuration, which is polled with conditional requests to run provider with
+// in-process evaluations. Failed polls are retried with the retry backoff, and headers set with WithHeaders or
+// WithTokenSource are sent with each poll. Offline files take precedence over the URL.
+// This is only useful with inProcess resolver type
+func WithSyncURL(url string) ProviderOption {
 	return func(p *Provider) {
-		p.providerConfiguration.OfflineFlagSourcePath = path
+		p.providerConfiguration.SyncURL = url
+	}
+}
+
+// WithSyncPollInterval sets the interval between polls of the URL set with WithSyncURL. Defaults to 5 seconds
+func WithSyncPollInterval(interval time.Duration) ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.SyncPollInterval = interval
+	}
+}
+
+// WithSnapshotPath sets a file persisting the last flag configuration received from the gRPC sync or the URL set with
+// WithSyncURL. If the sync is not available at startup, the provider serves flags of the snapshot in the stale state
+// after the first failed connection attempt, and becomes ready with the first sync. Connection attempts continue
+// without limits while a snapshot is set.
+// This is only useful with inProcess resolver type
+func WithSnapshotPath(path string) ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.SnapshotPath = path
+	}
+}
+
+// WithCustomEvaluator registers a JSONLogic operator with the given name, which can be used in targeting rules.
+// evaluate is called with the arguments of the operator and the evaluation context. Names of JSONLogic and flagd
+// operators are reserved, and registering them fails the provider initialization. Operators are registered globally,
+// hence they are shared by all providers of the process.
+// This is only useful with inProcess resolver type
+func WithCustomEvaluator(name string, evaluate func(values, data interface{}) interface{}) ProviderOption {
+	return func(p *Provider) {
+		if p.providerConfiguration.CustomEvaluators == nil {
+			p.providerConfiguration.CustomEvaluators = make(map[string]process.CustomEvaluator)
+		}
+		p.providerConfiguration.CustomEvaluators[name] = evaluate
+	}
+}
+
+// WithContextEnricher adds attributes to the evaluation context of each evaluation. static attributes are added as
+// given, and dynamic is called with the context of each evaluation. Either may be nil. Enrichers are applied in the
+// order of their options, and dynamic attributes override static attributes with the same key. By default,
+// attributes of the caller's evaluation context take precedence, see WithContextPrecedence
+func WithContextEnricher(static map[string]interface{}, dynamic ContextEnricher) ProviderOption {
+	return func(p *Provider) {
+		enrichment := contextEnrichment{dynamic: dynamic}
+		if len(static) > 0 {
+			enrichment.static = make(map[string]interface{}, len(static))
+			for k, v := range static {
+				enrichment.static[k] = v
+			}
+		}
+
+		p.providerConfiguration.ContextEnrichments = append(p.providerConfiguration.ContextEnrichments, enrichment)
+	}
+}
+
+// WithContextPrecedence decides whether attributes of the caller's evaluation context or attributes of
+// WithContextEnricher win if their keys collide. Defaults to CallerContextPrecedence
+func WithContextPrecedence(precedence ContextPrecedence) ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.ContextPrecedence = precedence
+	}
+}
+
+// WithObserver sets an observer notified about the cache, the connection and the flag syncs of the provider, see
+// NewOtelObserver for an observer recording OpenTelemetry metrics
+func WithObserver(observer Observer) ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.Observer = observer
+	}
+}
+
+// WithStrictValidation rejects flag configurations which do not conform to the flagd flag definition schema, and
+// keeps the last valid configuration of the source. By default, schema violations are logged and the valid parts of a
+// configuration are applied. Rejected configurations emit an event with openfeature.ProviderError describing the
+// violations.
+// This is only useful with inProcess resolver type
+func WithStrictValidation() ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.StrictValidation = true
+	}
+}
+
+// WithProtocol sets the wire protocol of the rpc resolver. Defaults to ProtocolConnect. Plain gRPC without TLS
+// requires WithH2C.
+// This is only useful with RPC resolver type
+func WithProtocol(protocol Protocol) ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.Protocol = protocol
+	}
+}
+
+// WithCompression compresses requests of the rpc resolver, such as with CompressionGzip. Responses are decompressed
+// regardless of this option.
+// This is only useful with RPC resolver type
+func WithCompression(compression string) ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.Compression = compression
+	}
+}
+
+// WithH2C uses HTTP/2 over cleartext connections, such as to a flagd sidecar without TLS. It can't be combined with
+// TLS, where HTTP/2 is negotiated.
+// This is only useful with RPC resolver type
+func WithH2C() ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.H2C = true
+	}
+}
+
+// WithShadowEvaluation evaluates the given fraction of evaluations, between 0 and 1, with a shadow resolver in the
+// background, and reports results differing in value, variant or reason to onMismatch. Mismatches are logged if
+// onMismatch is nil. The shadow resolver is the other resolver type, configured like the provider with the given
+// options applied, hence the resolver type may be overridden. Snapshot and cache files as well as the observer of the
+// provider are not used by the shadow resolver. Concurrent shadow evaluations are bounded, and evaluations are only
+// shadowed while the shadow resolver is ready. Sample rates outside of [0, 1] fail the initialization of the provider
+func WithShadowEvaluation(sampleRate float64, onMismatch ShadowMismatchHandler, opts ...ProviderOption) ProviderOption {
+	return func(p *Provider) {
+		if sampleRate < 0 || sampleRate > 1 || math.IsNaN(sampleRate) {
+			err := fmt.Errorf("shadow evaluation sample rate %v is not between 0 and 1", sampleRate)
+			p.providerConfiguration.err = errors.Join(p.providerConfiguration.err, err)
+			return
+		}
+
+		p.providerConfiguration.Shadow = &shadowConfiguration{
+			sampleRate: sampleRate,
+			onMismatch: onMismatch,
+			options:    opts,
+		}
 	}
 }
 
@@ -302,6 +729,16 @@ func WithSelector(selector string) ProviderOption {
 	}
 }
 
+// WithSelectors sets selectors of flag sets to be synced with InProcess flag sync calls, each with its own sync
+// stream. If multiple selectors define a flag, the flag of the selector given last takes precedence. Resolutions carry
+// the selector of their flag in the "scope" metadata. A selector set with WithSelector has the lowest precedence.
+// This is only useful with inProcess resolver type
+func WithSelectors(selectors ...string) ProviderOption {
+	return func(p *Provider) {
+		p.providerConfiguration.Selectors = selectors
+	}
+}
+
 // FromEnv sets the provider configuration from environment variables (if set)
 func FromEnv() ProviderOption {
 	return func(p *Provider) {
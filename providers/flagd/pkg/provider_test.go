@@ -1,11 +1,22 @@
 package flagd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-logr/logr"
 	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/cache"
 	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/mock"
+	rpcService "github.com/open-feature/go-sdk-contrib/providers/flagd/pkg/service/rpc"
 	of "github.com/open-feature/go-sdk/openfeature"
 	"go.uber.org/mock/gomock"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewProvider(t *testing.T) {
@@ -14,6 +25,7 @@ func TestNewProvider(t *testing.T) {
 		expectPort          uint16
 		expectHost          string
 		expectCacheType     cache.Type
+		expectCacheTTL      time.Duration
 		expectCertPath      string
 		expectMaxRetries    int
 		expectCacheSize     int
@@ -27,6 +39,7 @@ func TestNewProvider(t *testing.T) {
 			expectPort:          defaultPort,
 			expectHost:          defaultHost,
 			expectCacheType:     defaultCache,
+			expectCacheTTL:      defaultCacheTTL,
 			expectCertPath:      "",
 			expectMaxRetries:    defaultMaxEventStreamRetries,
 			expectCacheSize:     defaultMaxCacheSize,
@@ -39,6 +52,7 @@ func TestNewProvider(t *testing.T) {
 			expectPort:          9090,
 			expectHost:          "myHost",
 			expectCacheType:     cache.LRUValue,
+			expectCacheTTL:      defaultCacheTTL,
 			expectCertPath:      "/path",
 			expectMaxRetries:    2,
 			expectCacheSize:     2500,
@@ -55,6 +69,22 @@ func TestNewProvider(t *testing.T) {
 				WithPort(9090),
 			},
 		},
+		{
+			name:                "with ttl cache",
+			expectPort:          defaultPort,
+			expectHost:          defaultHost,
+			expectCacheType:     cache.TTLValue,
+			expectCacheTTL:      30 * time.Second,
+			expectCertPath:      "",
+			expectMaxRetries:    defaultMaxEventStreamRetries,
+			expectCacheSize:     500,
+			expectOtelIntercept: false,
+			expectSocketPath:    "",
+			expectTlsEnabled:    false,
+			options: []ProviderOption{
+				WithTTLCache(500, 30*time.Second),
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -98,6 +128,11 @@ func TestNewProvider(t *testing.T) {
 					test.expectCacheType, config.CacheType)
 			}
 
+			if config.CacheTTL != test.expectCacheTTL {
+				t.Errorf("incorrect configuration CacheTTL, expected %v, got %v",
+					test.expectCacheTTL, config.CacheTTL)
+			}
+
 			if config.Host != test.expectHost {
 				t.Errorf("incorrect configuration Host, expected %v, got %v",
 					test.expectHost, config.Host)
@@ -114,6 +149,29 @@ func TestNewProvider(t *testing.T) {
 	}
 }
 
+func TestSelectorsFromEnv(t *testing.T) {
+	tests := map[string]struct {
+		env             string
+		expectSelector  string
+		expectSelectors []string
+	}{
+		"single selector":    {env: "app=myapp", expectSelector: "app=myapp"},
+		"multiple selectors": {env: "base, team", expectSelectors: []string{"base", "team"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(flagdSourceSelectorEnvironmentVariableName, test.env)
+
+			config := NewProvider(FromEnv()).providerConfiguration
+			if config.Selector != test.expectSelector || !reflect.DeepEqual(config.Selectors, test.expectSelectors) {
+				t.Errorf("expected selector %q and selectors %v, got %q and %v",
+					test.expectSelector, test.expectSelectors, config.Selector, config.Selectors)
+			}
+		})
+	}
+}
+
 func TestEventHandling(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -143,11 +201,6 @@ func TestEventHandling(t *testing.T) {
 			ProviderName: "flagd",
 			EventType:    of.ProviderConfigChange,
 		}
-
-		customChan <- of.Event{
-			ProviderName: "flagd",
-			EventType:    of.ProviderError,
-		}
 	}()
 
 	// Check initial readiness
@@ -166,6 +219,13 @@ func TestEventHandling(t *testing.T) {
 		t.Errorf("expected status to be ready, but got %v", provider.Status())
 	}
 
+	go func() {
+		customChan <- of.Event{
+			ProviderName: "flagd",
+			EventType:    of.ProviderError,
+		}
+	}()
+
 	event = <-provider.EventChannel()
 	if event.EventType != of.ProviderError {
 		t.Errorf("expected event %v, got %v", of.ProviderError, event.EventType)
@@ -215,3 +275,350 @@ func TestInitializeOnlyOnce(t *testing.T) {
 	}
 
 }
+
+func TestEventHandlingStopsOnShutdown(t *testing.T) {
+	// given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventChan := make(chan of.Event)
+
+	svcMock := mock.NewMockIService(ctrl)
+	svcMock.EXPECT().Init().Times(2)
+	svcMock.EXPECT().EventChannel().Return(eventChan).AnyTimes()
+	svcMock.EXPECT().Shutdown().Times(2)
+
+	provider := NewProvider()
+	provider.service = svcMock
+
+	// when - provider is initialized again after a shutdown
+	for i := 0; i < 2; i++ {
+		go func() {
+			eventChan <- of.Event{
+				ProviderName: "flagd",
+				EventType:    of.ProviderReady,
+			}
+		}()
+
+		if err := provider.Init(of.EvaluationContext{}); err != nil {
+			t.Fatal("error initialization provider", err)
+		}
+		provider.Shutdown()
+	}
+
+	// let stopped event handling observe the shutdown
+	time.Sleep(50 * time.Millisecond)
+
+	// then - no event handling remains to receive service events
+	select {
+	case eventChan <- of.Event{ProviderName: "flagd", EventType: of.ProviderConfigChange}:
+		t.Fatal("expected no event handling after shutdown")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestInitWithDeadline(t *testing.T) {
+	// given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventChan := make(chan of.Event)
+
+	svcMock := mock.NewMockIService(ctrl)
+	svcMock.EXPECT().Init().Times(1)
+	svcMock.EXPECT().EventChannel().Return(eventChan).AnyTimes()
+
+	provider := NewProvider(WithDeadline(50 * time.Millisecond))
+	provider.service = svcMock
+
+	// when - service does not become ready within the deadline
+	err := provider.Init(of.EvaluationContext{})
+
+	// then
+	if err == nil {
+		t.Fatal("expected initialization to fail once the deadline is exceeded")
+	}
+
+	if provider.Status() != of.NotReadyState {
+		t.Errorf("expected status to be not ready, but got %v", provider.Status())
+	}
+
+	// when - service becomes ready in the background
+	go func() {
+		eventChan <- of.Event{
+			ProviderName: "flagd",
+			EventType:    of.ProviderReady,
+		}
+	}()
+
+	// then - ready event is emitted and provider is ready
+	select {
+	case event := <-provider.EventChannel():
+		if event.EventType != of.ProviderReady {
+			t.Errorf("expected event %v, got %v", of.ProviderReady, event.EventType)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for ready event")
+	}
+
+	if provider.Status() != of.ReadyState {
+		t.Errorf("expected status to be ready, but got %v", provider.Status())
+	}
+}
+
+func TestFlagIntrospection(t *testing.T) {
+	// given
+	offlinePath := filepath.Join(t.TempDir(), "flags.json")
+	err := os.WriteFile(offlinePath, []byte(`{
+		"flags": {
+			"myBoolFlag": {
+				"state": "ENABLED",
+				"variants": {"on": true, "off": false},
+				"defaultVariant": "on"
+			},
+			"myStringFlag": {
+				"state": "DISABLED",
+				"variants": {"a": "a", "b": "b"},
+				"defaultVariant": "b",
+				"targeting": {"if": [true, "a", "b"]}
+			}
+		}
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewProvider(WithInProcessResolver(), WithOfflineFilePath(offlinePath))
+
+	// when
+	err = provider.Init(of.EvaluationContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer provider.Shutdown()
+
+	// then
+	flags, err := provider.Flags()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(flags) != 2 || flags[0].Key != "myBoolFlag" || flags[1].Key != "myStringFlag" {
+		t.Fatalf("expected flags ordered by key, got %v", flags)
+	}
+
+	definition, found, err := provider.FlagDefinition("myStringFlag")
+	if err != nil || !found {
+		t.Fatalf("expected flag definition, got found %v and error %v", found, err)
+	}
+
+	if definition.State != "DISABLED" || definition.DefaultVariant != "b" || len(definition.Variants) != 2 {
+		t.Errorf("unexpected flag definition %v", definition)
+	}
+
+	if len(definition.Targeting) == 0 {
+		t.Error("expected targeting of the flag")
+	}
+
+	if definition.Source != offlinePath {
+		t.Errorf("expected source %s, got %s", offlinePath, definition.Source)
+	}
+
+	if definition.LastSync.IsZero() {
+		t.Error("expected time of the last sync")
+	}
+
+	_, found, err = provider.FlagDefinition("missing")
+	if err != nil || found {
+		t.Errorf("expected missing flag not to be found, got found %v and error %v", found, err)
+	}
+
+	// rpc resolvers do not load flags
+	_, err = NewProvider().Flags()
+	if !errors.Is(err, ErrIntrospectionUnsupported) {
+		t.Errorf("expected error %v, got %v", ErrIntrospectionUnsupported, err)
+	}
+}
+
+type ctxKey struct{}
+
+func TestContextEnricher(t *testing.T) {
+	enricher := WithContextEnricher(
+		map[string]interface{}{"region": "eu-west-1", "service": "checkout", "version": "1.0.0"},
+		func(ctx context.Context) map[string]interface{} {
+			return map[string]interface{}{"pod": ctx.Value(ctxKey{}), "version": "1.0.1"}
+		})
+
+	tests := []struct {
+		name     string
+		options  []ProviderOption
+		expected map[string]interface{}
+	}{
+		{
+			name:     "without enricher",
+			expected: map[string]interface{}{"targetingKey": "user", "region": "us-east-1"},
+		},
+		{
+			name:    "caller precedence",
+			options: []ProviderOption{enricher},
+			expected: map[string]interface{}{
+				"targetingKey": "user", "region": "us-east-1", "service": "checkout", "version": "1.0.1",
+				"pod": "pod-1"},
+		},
+		{
+			name:    "enricher precedence",
+			options: []ProviderOption{enricher, WithContextPrecedence(EnricherContextPrecedence)},
+			expected: map[string]interface{}{
+				"targetingKey": "user", "region": "eu-west-1", "service": "checkout", "version": "1.0.1",
+				"pod": "pod-1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ctx := context.WithValue(context.Background(), ctxKey{}, "pod-1")
+			evalCtx := map[string]interface{}{"targetingKey": "user", "region": "us-east-1"}
+
+			svcMock := mock.NewMockIService(ctrl)
+			svcMock.EXPECT().ResolveBoolean(ctx, "flag", false, gomock.Eq(test.expected)).
+				Return(of.BoolResolutionDetail{Value: true})
+
+			provider := NewProvider(test.options...)
+			provider.service = svcMock
+
+			provider.BooleanEvaluation(ctx, "flag", false, evalCtx)
+
+			if len(evalCtx) != 2 || evalCtx["region"] != "us-east-1" {
+				t.Errorf("expected evaluation context of the caller to be unmodified, got %v", evalCtx)
+			}
+		})
+	}
+}
+
+func TestShadowEvaluation(t *testing.T) {
+	// given - a shadow resolver with a different default variant of a flag
+	dir := t.TempDir()
+	flags := `{"flags": {"myBoolFlag": {
+		"state": "ENABLED", "variants": {"on": true, "off": false}, "defaultVariant": "%s"}}}`
+
+	servingPath := filepath.Join(dir, "serving.json")
+	shadowPath := filepath.Join(dir, "shadow.json")
+	if err := os.WriteFile(servingPath, []byte(fmt.Sprintf(flags, "on")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(shadowPath, []byte(fmt.Sprintf(flags, "off")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mismatches := make(chan ShadowMismatch, maxShadowEvaluations)
+	provider := NewProvider(
+		WithInProcessResolver(),
+		WithOfflineFilePath(servingPath),
+		WithShadowEvaluation(1, func(mismatch ShadowMismatch) {
+			mismatches <- mismatch
+		}, WithInProcessResolver(), WithOfflineFilePath(shadowPath)))
+
+	err := provider.Init(of.EvaluationContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer provider.Shutdown()
+
+	evalCtx := of.FlattenedContext{"targetingKey": "user"}
+
+	// when - evaluations are shadowed once the shadow resolver is ready
+	var mismatch ShadowMismatch
+	deadline := time.After(2 * time.Second)
+	for mismatch.FlagKey == "" {
+		detail := provider.BooleanEvaluation(context.Background(), "myBoolFlag", false, evalCtx)
+		if !detail.Value {
+			t.Fatal("expected the serving resolver's value")
+		}
+
+		select {
+		case mismatch = <-mismatches:
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("expected a shadow mismatch within an acceptable timeframe")
+		}
+	}
+
+	// then
+	if !reflect.DeepEqual(mismatch.Fields, []string{"value", "variant"}) {
+		t.Errorf("expected value and variant to mismatch, got %v", mismatch.Fields)
+	}
+
+	if mismatch.Serving.Value != true || mismatch.Shadow.Value != false {
+		t.Errorf("expected serving value true and shadow value false, got %v and %v",
+			mismatch.Serving.Value, mismatch.Shadow.Value)
+	}
+
+	fingerprint, err := rpcService.ContextFingerprint(evalCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mismatch.ContextFingerprint != fingerprint {
+		t.Errorf("expected context fingerprint %s, got %s", fingerprint, mismatch.ContextFingerprint)
+	}
+}
+
+func TestShadowEvaluationSampleRate(t *testing.T) {
+	for _, sampleRate := range []float64{-0.1, 1.5, math.NaN()} {
+		t.Run(fmt.Sprintf("%v", sampleRate), func(t *testing.T) {
+			provider := NewProvider(
+				WithInProcessResolver(),
+				WithOfflineFilePath(filepath.Join(t.TempDir(), "flags.json")),
+				WithShadowEvaluation(sampleRate, nil))
+
+			if provider.shadow != nil {
+				t.Error("expected no shadow evaluation with an invalid sample rate")
+			}
+
+			err := provider.Init(of.EvaluationContext{})
+			if err == nil || !strings.Contains(err.Error(), "sample rate") {
+				t.Errorf("expected initialization to fail with the invalid sample rate, got %v", err)
+			}
+		})
+	}
+}
+
+func TestShadowEvaluationShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventChan := make(chan of.Event, 1)
+	svcMock := mock.NewMockIService(ctrl)
+	svcMock.EXPECT().EventChannel().Return(eventChan).Times(2)
+	svcMock.EXPECT().Init().Times(2)
+	svcMock.EXPECT().Shutdown().Times(2)
+
+	shadow := &shadowEvaluation{service: svcMock, logger: logr.Discard()}
+
+	// given - a shadow evaluation which was initialized again after its shutdown
+	shadow.init()
+	shadow.shutdown()
+	shadow.init()
+
+	eventChan <- of.Event{EventType: of.ProviderReady}
+	deadline := time.After(time.Second)
+	for !shadow.ready.Load() {
+		select {
+		case <-deadline:
+			t.Fatal("expected the shadow service to become ready")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	// when
+	shadow.shutdown()
+
+	// then - readiness is no longer tracked
+	eventChan <- of.Event{EventType: of.ProviderReady}
+	time.Sleep(50 * time.Millisecond)
+
+	if shadow.ready.Load() || len(eventChan) != 1 {
+		t.Error("expected events of the shadow service not to be consumed after shutdown")
+	}
+}
@@ -0,0 +1,65 @@
+package process
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/open-feature/flagd/core/pkg/evaluator"
+	"github.com/open-feature/flagd/core/pkg/logger"
+)
+
+// CustomEvaluator is a JSONLogic operator usable in targeting rules. values are the arguments of the operator, and
+// data is the evaluation context of the evaluation
+type CustomEvaluator func(values, data interface{}) interface{}
+
+// reservedOperators are operators of JSONLogic, which must not be shadowed by custom evaluators
+var reservedOperators = map[string]bool{
+	"==": true, "===": true, "!=": true, "!==": true, ">": true, ">=": true, "<": true, "<=": true, "!": true,
+	"!!": true, "or": true, "and": true, "?:": true, "if": true, "in": true, "in_sorted": true, "cat": true,
+	"substr": true, "%": true, "abs": true, "max": true, "min": true, "+": true, "-": true, "*": true, "/": true,
+	"merge": true, "missing": true, "missing_some": true, "some": true, "filter": true, "map": true, "reduce": true,
+	"all": true, "none": true, "set": true, "var": true,
+}
+
+// evaluatorOptions returns the evaluators of flagd and the custom evaluators. Custom evaluators must not shadow
+// operators of JSONLogic or flagd. Note that operators are registered with JSONLogic, hence they are shared by all
+// in-process services
+func evaluatorOptions(log *logger.Logger, custom map[string]CustomEvaluator) (
+	[]evaluator.JSONEvaluatorOption, error) {
+	builtIn := map[string]CustomEvaluator{
+		"fractional":  evaluator.NewFractional(log).Evaluate,
+		"starts_with": evaluator.NewStringComparisonEvaluator(log).StartsWithEvaluation,
+		"ends_with":   evaluator.NewStringComparisonEvaluator(log).EndsWithEvaluation,
+		"sem_ver":     evaluator.NewSemVerComparison(log).SemVerEvaluation,
+	}
+
+	options := make([]evaluator.JSONEvaluatorOption, 0, len(builtIn)+len(custom))
+	for _, name := range sortedKeys(builtIn) {
+		options = append(options, evaluator.WithEvaluator(name, builtIn[name]))
+	}
+
+	for _, name := range sortedKeys(custom) {
+		switch {
+		case name == "":
+			return nil, fmt.Errorf("custom evaluator name must not be empty")
+		case reservedOperators[name] || builtIn[name] != nil:
+			return nil, fmt.Errorf("custom evaluator %s shadows a built-in operator", name)
+		case custom[name] == nil:
+			return nil, fmt.Errorf("custom evaluator %s has no evaluation function", name)
+		}
+
+		options = append(options, evaluator.WithEvaluator(name, custom[name]))
+	}
+
+	return options, nil
+}
+
+func sortedKeys(evaluators map[string]CustomEvaluator) []string {
+	keys := make([]string, 0, len(evaluators))
+	for k := range evaluators {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,148 @@
+package process
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	of "github.com/open-feature/go-sdk/openfeature"
+)
+
+var cidrFlags = `{
+	"flags": {
+		"internalUser": {
+			"state": "ENABLED",
+			"variants": {
+				"on": true,
+				"off": false
+			},
+			"defaultVariant": "off",
+			"targeting": {
+				"if": [{"cidr": [{"var": "ip"}, "10.0.0.0/8"]}, "on", "off"]
+			}
+		}
+	}
+}`
+
+// cidr evaluates whether the first argument is an IP address of the network of the second argument
+func cidr(values, _ interface{}) interface{} {
+	args, ok := values.([]interface{})
+	if !ok || len(args) != 2 {
+		return false
+	}
+
+	ip, _ := args[0].(string)
+	network, _ := args[1].(string)
+
+	_, ipNet, err := net.ParseCIDR(network)
+	if err != nil {
+		return false
+	}
+
+	parsed := net.ParseIP(ip)
+	return parsed != nil && ipNet.Contains(parsed)
+}
+
+func TestCustomEvaluator(t *testing.T) {
+	// given
+	offlinePath := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(offlinePath, []byte(cidrFlags), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service := NewInProcessService(Configuration{
+		OfflineFlagSource: offlinePath,
+		CustomEvaluators:  map[string]CustomEvaluator{"cidr": cidr},
+	})
+
+	// when
+	err = service.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer service.Shutdown()
+
+	awaitEvent(t, service, of.ProviderReady)
+
+	// then - targeting rules evaluate the custom operator
+	tests := map[string]bool{
+		"10.1.2.3":    true,
+		"192.168.1.1": false,
+	}
+
+	for ip, expected := range tests {
+		detail := service.ResolveBoolean(
+			context.Background(), "internalUser", !expected, map[string]interface{}{"ip": ip})
+
+		if detail.Value != expected {
+			t.Errorf("expected %v for ip %s, got %v", expected, ip, detail.Value)
+		}
+
+		if detail.Reason != of.TargetingMatchReason {
+			t.Errorf("expected reason %s for ip %s, got %s", of.TargetingMatchReason, ip, detail.Reason)
+		}
+	}
+}
+
+func TestCustomEvaluatorStrictValidation(t *testing.T) {
+	// given - targeting rules with a custom operator, which the flagd schema does not know
+	offlinePath := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(offlinePath, []byte(cidrFlags), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service := NewInProcessService(Configuration{
+		OfflineFlagSource: offlinePath,
+		CustomEvaluators:  map[string]CustomEvaluator{"cidr": cidr},
+		StrictValidation:  true,
+	})
+
+	// when
+	err = service.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer service.Shutdown()
+
+	// then - the configuration is accepted, as the operator is registered
+	awaitEvent(t, service, of.ProviderReady)
+
+	detail := service.ResolveBoolean(
+		context.Background(), "internalUser", false, map[string]interface{}{"ip": "10.1.2.3"})
+	if !detail.Value || detail.Reason != of.TargetingMatchReason {
+		t.Errorf("expected targeting match of the custom operator, got %v", detail)
+	}
+}
+
+func TestCustomEvaluatorValidation(t *testing.T) {
+	tests := map[string]map[string]CustomEvaluator{
+		"jsonlogic operator": {"in": cidr},
+		"flagd operator":     {"sem_ver": cidr},
+		"empty name":         {"": cidr},
+		"missing function":   {"cidr": nil},
+	}
+
+	for name, evaluators := range tests {
+		t.Run(name, func(t *testing.T) {
+			service := NewInProcessService(Configuration{
+				OfflineFlagSource: filepath.Join(t.TempDir(), "config.json"),
+				CustomEvaluators:  evaluators,
+			})
+
+			err := service.Init()
+			if err == nil {
+				service.Shutdown()
+				t.Fatal("expected initialization to fail")
+			}
+
+			if !strings.Contains(err.Error(), "custom evaluator") {
+				t.Errorf("expected custom evaluator error, got %s", err.Error())
+			}
+		})
+	}
+}
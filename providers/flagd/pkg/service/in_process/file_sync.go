@@ -0,0 +1,149 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	parallel "sync"
+
+	"github.com/open-feature/flagd/core/pkg/logger"
+	"github.com/open-feature/flagd/core/pkg/sync"
+	"github.com/open-feature/flagd/core/pkg/sync/file"
+)
+
+// offlineFileExtensions are the extensions of flag files picked up from directories
+var offlineFileExtensions = map[string]bool{".json": true, ".yaml": true, ".yml": true}
+
+// expandOfflineSources resolves offline flag sources to flag files. A source is either a file, a directory or a glob
+// pattern. Files of directories and glob matches are sorted lexicographically, hence the order of the returned files
+// is deterministic. Files appearing multiple times are kept at their last position.
+func expandOfflineSources(sources []string) ([]string, error) {
+	var files []string
+
+	for _, source := range sources {
+		if strings.ContainsAny(source, "*?[") {
+			matches, err := filepath.Glob(source)
+			if err != nil {
+				return nil, fmt.Errorf("invalid offline flag source pattern %s: %w", source, err)
+			}
+			sort.Strings(matches)
+			files = append(files, matches...)
+			continue
+		}
+
+		info, err := os.Stat(source)
+		if err != nil || !info.IsDir() {
+			// missing files are reported by the file sync
+			files = append(files, source)
+			continue
+		}
+
+		entries, err := os.ReadDir(source)
+		if err != nil {
+			return nil, fmt.Errorf("error reading offline flag source directory %s: %w", source, err)
+		}
+
+		// os.ReadDir returns entries sorted by filename
+		for _, entry := range entries {
+			if !entry.IsDir() && offlineFileExtensions[filepath.Ext(entry.Name())] {
+				files = append(files, filepath.Join(source, entry.Name()))
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no flag files found for offline flag sources %s", strings.Join(sources, ", "))
+	}
+
+	return dedupeKeepLast(files), nil
+}
+
+func dedupeKeepLast(files []string) []string {
+	last := make(map[string]int, len(files))
+	for i, f := range files {
+		last[f] = i
+	}
+
+	deduped := make([]string, 0, len(last))
+	for i, f := range files {
+		if last[f] == i {
+			deduped = append(deduped, f)
+		}
+	}
+
+	return deduped
+}
+
+// newFileSync creates a sync.ISync watching the files. Multiple files are watched independently
+func newFileSync(files []string, log *logger.Logger) sync.ISync {
+	syncs := make([]sync.ISync, 0, len(files))
+	for _, f := range files {
+		syncs = append(syncs, &file.Sync{
+			URI:    f,
+			Logger: log,
+			Mux:    &parallel.RWMutex{},
+		})
+	}
+
+	if len(syncs) == 1 {
+		return syncs[0]
+	}
+
+	return &multiSync{syncs: syncs}
+}
+
+// multiSync implements sync.ISync by running multiple syncs concurrently
+type multiSync struct {
+	syncs []sync.ISync
+}
+
+func (m *multiSync) Init(ctx context.Context) error {
+	for _, s := range m.syncs {
+		if err := s.Init(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Sync runs all syncs until they returned, and returns their joined errors
+func (m *multiSync) Sync(ctx context.Context, dataSync chan<- sync.DataSync) error {
+	var wg parallel.WaitGroup
+	errs := make([]error, len(m.syncs))
+
+	for i, s := range m.syncs {
+		wg.Add(1)
+		go func(i int, s sync.ISync) {
+			defer wg.Done()
+			errs[i] = s.Sync(ctx, dataSync)
+		}(i, s)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (m *multiSync) ReSync(ctx context.Context, dataSync chan<- sync.DataSync) error {
+	var errs []error
+	for _, s := range m.syncs {
+		errs = append(errs, s.ReSync(ctx, dataSync))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m *multiSync) IsReady() bool {
+	for _, s := range m.syncs {
+		if !s.IsReady() {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,194 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"buf.build/gen/go/open-feature/flagd/grpc/go/flagd/sync/v1/syncv1grpc"
+	v1 "buf.build/gen/go/open-feature/flagd/protocolbuffers/go/flagd/sync/v1"
+	"github.com/open-feature/flagd/core/pkg/logger"
+	"github.com/open-feature/flagd/core/pkg/sync"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/headers"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/retry"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/tlsconfig"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// connectionListener is notified about connection state changes of a sync source
+type connectionListener interface {
+	// onConnected is called once a sync stream of the source delivered its first payload
+	onConnected(source string)
+	// onConnectionLost is called once a sync stream of the source which delivered payloads fails
+	onConnectionLost(source string)
+	// onRetriesExhausted is called once the connection attempts of the retry policy are exhausted
+	onRetriesExhausted(source string, err error)
+	// onRetry is called before a failed connection attempt is retried after the delay
+	onRetry(source string, delay time.Duration)
+}
+
+// grpcSync implements sync.ISync for flagd's gRPC sync service. Unlike the gRPC sync of flagd core, the sync stream is
+// re-established following the configured retry policy and connection state changes are reported to the listener.
+type grpcSync struct {
+	headers     *headers.Injector
+	listener    connectionListener
+	logger      *logger.Logger
+	retryPolicy retry.Policy
+	secure      bool
+	tlsOptions  tlsconfig.Options
+	selector    string
+	uri         string
+	// source identifies the flags of the sync stream, as multiple selectors are synced from the same uri
+	source string
+
+	client syncv1grpc.FlagSyncServiceClient
+	ready  atomic.Bool
+}
+
+func (g *grpcSync) Init(ctx context.Context) error {
+	tCredentials, err := g.transportCredentials()
+	if err != nil {
+		err := fmt.Errorf("error building transport credentials: %w", err)
+		g.logger.Error(err.Error())
+		return err
+	}
+
+	dialOptions := []grpc.DialOption{grpc.WithTransportCredentials(tCredentials)}
+	if g.headers != nil {
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(g.headers.PerRPCCredentials()))
+	}
+
+	// derive reusable client connection
+	rpcCon, err := grpc.DialContext(ctx, g.uri, dialOptions...)
+	if err != nil {
+		err := fmt.Errorf("error initiating grpc client connection: %w", err)
+		g.logger.Error(err.Error())
+		return err
+	}
+
+	// release the connection once syncing ends
+	go func() {
+		<-ctx.Done()
+		_ = rpcCon.Close()
+	}()
+
+	g.client = syncv1grpc.NewFlagSyncServiceClient(rpcCon)
+
+	return nil
+}
+
+// transportCredentials derives the credentials of the sync connection. Rotated certificates are picked up with new
+// connections
+func (g *grpcSync) transportCredentials() (credentials.TransportCredentials, error) {
+	if !g.secure {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsConfig, err := tlsconfig.New(g.tlsOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// Sync opens the sync stream and re-establishes it with the retry policy until the context is done.
+// If attempts are exhausted and the policy does not retry without limits, an error is returned.
+func (g *grpcSync) Sync(ctx context.Context, dataSync chan<- sync.DataSync) error {
+	counter := retry.NewCounter(g.retryPolicy)
+	exhausted := false
+
+	for {
+		err := g.syncFlags(ctx, dataSync, counter)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		g.logger.Warn(fmt.Sprintf("error with sync stream of grpc target %s: %s", g.uri, err.Error()))
+
+		if counter.Retry() {
+			exhausted = false
+		} else {
+			if !exhausted {
+				exhausted = true
+				g.listener.onRetriesExhausted(g.source, err)
+			}
+
+			if !counter.Unlimited() {
+				return fmt.Errorf("sync connection attempts exhausted: %w", err)
+			}
+		}
+
+		delay := counter.Sleep()
+		g.listener.onRetry(g.source, delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (g *grpcSync) ReSync(ctx context.Context, dataSync chan<- sync.DataSync) error {
+	res, err := g.client.FetchAllFlags(ctx, &v1.FetchAllFlagsRequest{Selector: g.selector})
+	if err != nil {
+		err = fmt.Errorf("error fetching all flags: %w", err)
+		g.logger.Error(err.Error())
+		return err
+	}
+
+	dataSync <- sync.DataSync{
+		FlagData: res.GetFlagConfiguration(),
+		Source:   g.source,
+		Type:     sync.ALL,
+	}
+
+	return nil
+}
+
+func (g *grpcSync) IsReady() bool {
+	return g.ready.Load()
+}
+
+// syncFlags opens a single sync stream and push its payloads through the dataSync channel until the stream fails
+func (g *grpcSync) syncFlags(ctx context.Context, dataSync chan<- sync.DataSync, counter *retry.Counter) error {
+	stream, err := g.client.SyncFlags(ctx, &v1.SyncFlagsRequest{Selector: g.selector})
+	if err != nil {
+		return fmt.Errorf("unable to sync flags: %w", err)
+	}
+
+	connected := false
+	for {
+		data, err := stream.Recv()
+		if err != nil {
+			if connected {
+				g.listener.onConnectionLost(g.source)
+			}
+			return fmt.Errorf("error receiving payload from stream: %w", err)
+		}
+
+		// reset retry counters once the stream is proven to be functional
+		counter.Reset()
+		if !connected {
+			connected = true
+			g.ready.Store(true)
+			g.listener.onConnected(g.source)
+		}
+
+		select {
+		case dataSync <- sync.DataSync{
+			FlagData: data.FlagConfiguration,
+			Source:   g.source,
+			Type:     sync.ALL,
+		}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		g.logger.Debug("received full configuration payload")
+	}
+}
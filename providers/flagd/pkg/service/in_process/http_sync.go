@@ -0,0 +1,204 @@
+package process
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	parallel "sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/open-feature/flagd/core/pkg/logger"
+	"github.com/open-feature/flagd/core/pkg/sync"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/headers"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/retry"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/tlsconfig"
+)
+
+// defaultPollInterval is the interval between polls of an HTTP sync source, matching flagd's HTTP sync
+const defaultPollInterval = 5 * time.Second
+
+// httpSync implements sync.ISync by polling flag configurations from a URL. Conditional requests avoid transferring
+// unchanged configurations, and failed polls are retried following the retry policy.
+type httpSync struct {
+	client       *http.Client
+	headers      *headers.Injector
+	listener     connectionListener
+	logger       *logger.Logger
+	pollInterval time.Duration
+	retryPolicy  retry.Policy
+	tlsOptions   tlsconfig.Options
+	uri          string
+
+	// pollMtx guards the validators of the previous response
+	pollMtx      parallel.Mutex
+	etag         string
+	lastModified string
+	lastHash     [sha256.Size]byte
+	ready        atomic.Bool
+}
+
+func (h *httpSync) Init(_ context.Context) error {
+	tlsConfig, err := tlsconfig.New(h.tlsOptions)
+	if err != nil {
+		err := fmt.Errorf("error building tls configuration: %w", err)
+		h.logger.Error(err.Error())
+		return err
+	}
+
+	if _, err := http.NewRequest(http.MethodGet, h.uri, nil); err != nil {
+		return fmt.Errorf("invalid sync url %s: %w", h.uri, err)
+	}
+
+	if h.pollInterval <= 0 {
+		h.pollInterval = defaultPollInterval
+	}
+
+	h.client = &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}
+
+	return nil
+}
+
+// Sync polls the flag configuration until the context is done. If polls keep failing and the retry policy does not
+// retry without limits, an error is returned once attempts are exhausted.
+func (h *httpSync) Sync(ctx context.Context, dataSync chan<- sync.DataSync) error {
+	counter := retry.NewCounter(h.retryPolicy)
+	connected := false
+	exhausted := false
+
+	for {
+		err := h.poll(ctx, dataSync, false)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		delay := h.pollInterval
+		if err == nil {
+			counter.Reset()
+			exhausted = false
+			if !connected {
+				connected = true
+				h.listener.onConnected(h.uri)
+			}
+		} else {
+			h.logger.Warn(fmt.Sprintf("error polling flags from %s: %s", h.uri, err.Error()))
+			if connected {
+				connected = false
+				h.listener.onConnectionLost(h.uri)
+			}
+
+			if !counter.Retry() {
+				if !exhausted {
+					exhausted = true
+					h.listener.onRetriesExhausted(h.uri, err)
+				}
+
+				if !counter.Unlimited() {
+					return fmt.Errorf("sync poll attempts exhausted: %w", err)
+				}
+			}
+
+			delay = counter.Sleep()
+			h.listener.onRetry(h.uri, delay)
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (h *httpSync) ReSync(ctx context.Context, dataSync chan<- sync.DataSync) error {
+	err := h.poll(ctx, dataSync, true)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("error fetching all flags from %s: %s", h.uri, err.Error()))
+	}
+
+	return err
+}
+
+func (h *httpSync) IsReady() bool {
+	return h.ready.Load()
+}
+
+// poll fetches the flag configuration and sends it through the dataSync channel if it changed. Unless forced, the
+// request is conditional on the validators of the previous response
+func (h *httpSync) poll(ctx context.Context, dataSync chan<- sync.DataSync, force bool) error {
+	h.pollMtx.Lock()
+	defer h.pollMtx.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.uri, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if h.headers != nil {
+		reqHeaders, err := h.headers.Get(ctx)
+		if err != nil {
+			return err
+		}
+		for k, v := range reqHeaders {
+			req.Header.Set(k, v)
+		}
+	}
+
+	if !force {
+		if h.etag != "" {
+			req.Header.Set("If-None-Match", h.etag)
+		}
+		if h.lastModified != "" {
+			req.Header.Set("If-Modified-Since", h.lastModified)
+		}
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		h.logger.Debug(fmt.Sprintf("flags of %s not modified", h.uri))
+		return nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %w", err)
+	}
+
+	h.etag = resp.Header.Get("ETag")
+	h.lastModified = resp.Header.Get("Last-Modified")
+
+	// sources without validators respond with the full configuration, skip unchanged configurations
+	hash := sha256.Sum256(body)
+	if !force && h.ready.Load() && hash == h.lastHash {
+		return nil
+	}
+	h.lastHash = hash
+
+	select {
+	case dataSync <- sync.DataSync{FlagData: string(body), Source: h.uri, Type: sync.ALL}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	h.ready.Store(true)
+	h.logger.Debug("received full configuration payload")
+
+	return nil
+}
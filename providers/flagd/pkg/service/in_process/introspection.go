@@ -0,0 +1,78 @@
+package process
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/open-feature/flagd/core/pkg/model"
+)
+
+// FlagDefinition is the definition of a flag loaded by the in-process service
+type FlagDefinition struct {
+	Key            string
+	State          string
+	DefaultVariant string
+	Variants       map[string]interface{}
+	Targeting      json.RawMessage
+	// Source is the URI of the sync source the flag was loaded from
+	Source string
+	// Selector is the selector of the flag's sync source, if any
+	Selector string
+	// LastSync is the time of the last sync of the flag's source. It is zero for flags of a snapshot
+	LastSync time.Time
+}
+
+// Flags returns the definitions of all loaded flags, ordered by key
+func (i *InProcess) Flags() []FlagDefinition {
+	flags := i.flagStore.GetAll()
+
+	definitions := make([]FlagDefinition, 0, len(flags))
+	for key, flag := range flags {
+		definitions = append(definitions, i.flagDefinition(key, flag))
+	}
+
+	sort.Slice(definitions, func(a, b int) bool {
+		return definitions[a].Key < definitions[b].Key
+	})
+
+	return definitions
+}
+
+// FlagDefinition returns the definition of the loaded flag with the key, if any
+func (i *InProcess) FlagDefinition(key string) (FlagDefinition, bool) {
+	flag, ok := i.flagStore.Get(key)
+	if !ok {
+		return FlagDefinition{}, false
+	}
+
+	return i.flagDefinition(key, flag), true
+}
+
+// flagDefinition converts a flag of the store. Variants and targeting are copied, as flags of the store are shared
+func (i *InProcess) flagDefinition(key string, flag model.Flag) FlagDefinition {
+	variants := make(map[string]interface{}, len(flag.Variants))
+	for variant, value := range flag.Variants {
+		variants[variant] = value
+	}
+
+	var targeting json.RawMessage
+	if len(flag.Targeting) > 0 {
+		targeting = append(targeting, flag.Targeting...)
+	}
+
+	i.lastSyncMtx.RLock()
+	lastSync := i.lastSync[flag.Source]
+	i.lastSyncMtx.RUnlock()
+
+	return FlagDefinition{
+		Key:            key,
+		State:          flag.State,
+		DefaultVariant: flag.DefaultVariant,
+		Variants:       variants,
+		Targeting:      targeting,
+		Source:         flag.Source,
+		Selector:       i.flagStore.SelectorForFlag(flag),
+		LastSync:       lastSync,
+	}
+}
@@ -2,19 +2,27 @@ package process
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/go-logr/logr"
 	"github.com/open-feature/flagd/core/pkg/evaluator"
 	"github.com/open-feature/flagd/core/pkg/logger"
 	"github.com/open-feature/flagd/core/pkg/model"
 	"github.com/open-feature/flagd/core/pkg/store"
 	"github.com/open-feature/flagd/core/pkg/sync"
-	"github.com/open-feature/flagd/core/pkg/sync/file"
-	"github.com/open-feature/flagd/core/pkg/sync/grpc"
-	"github.com/open-feature/flagd/core/pkg/sync/grpc/credentials"
+	flagchanges "github.com/open-feature/go-sdk-contrib/providers/flagd/internal/changes"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/headers"
+	providerLogger "github.com/open-feature/go-sdk-contrib/providers/flagd/internal/logger"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/observer"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/retry"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/tlsconfig"
 	of "github.com/open-feature/go-sdk/openfeature"
 	"golang.org/x/exp/maps"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sort"
+	"strings"
 	parallel "sync"
+	"sync/atomic"
+	"time"
 )
 
 // InProcess service implements flagd flag evaluation in-process.
@@ -25,8 +33,31 @@ type InProcess struct {
 	listenerShutdown chan interface{}
 	logger           *logger.Logger
 	serviceMetadata  map[string]interface{}
+	sources          []string
+	strictValidation bool
+	customOperators  []string
 	sync             sync.ISync
 	syncEnd          context.CancelFunc
+	initErr          error
+
+	// ready is set once each source delivered its flags
+	ready            atomic.Bool
+	gracePeriod      *retry.GracePeriod
+	retryGracePeriod time.Duration
+	// lostSources holds the sources whose connection is lost. The connection is restored once all are connected
+	lostSources    map[string]struct{}
+	lostSourcesMtx parallel.Mutex
+
+	// degraded is set while flags of the snapshot are served, as the sync source was not available at startup
+	degraded     atomic.Bool
+	snapshot     *snapshot
+	snapshotOnce parallel.Once
+
+	flagStore *store.Flags
+	observer  observer.Observer
+	// lastSync holds the time of the last sync of each source
+	lastSync    map[string]time.Time
+	lastSyncMtx parallel.RWMutex
 }
 
 type Configuration struct {
@@ -34,95 +65,185 @@ type Configuration struct {
 	Port              any
 	Selector          string
 	TLSEnabled        bool
+	CertificatePath   string
+	ClientCertPath    string
+	ClientKeyPath     string
+	ServerName        string
+	Headers           map[string]string
+	TokenSource       headers.TokenSource
+	SyncURL           string
+	SyncPollInterval  time.Duration
 	OfflineFlagSource string
+	// OfflineFlagSources are files, directories or glob patterns of flag files. Flags of later sources take
+	// precedence over flags of earlier sources with the same key. OfflineFlagSource, if set, has the lowest precedence
+	OfflineFlagSources []string
+	Logger             logr.Logger
+	RetryPolicy        retry.Policy
+	RetryGracePeriod   time.Duration
+	// Selectors are selectors of flag sets, each synced with its own stream of the gRPC sync service. Flags of later
+	// selectors take precedence over flags of earlier selectors with the same key. Selector, if set, has the lowest
+	// precedence
+	Selectors []string
+	// SnapshotPath is a file persisting the last flag configuration received from a gRPC or HTTP sync source. It is
+	// served if the sync source is not available at startup
+	SnapshotPath string
+	// CustomEvaluators are JSONLogic operators usable in targeting rules, in addition to the operators of flagd
+	CustomEvaluators map[string]CustomEvaluator
+	Observer         observer.Observer
+	// StrictValidation rejects flag configurations which do not conform to the flagd flag definition schema, hence
+	// the last valid configuration of a source is kept
+	StrictValidation bool
 }
 
 func NewInProcessService(cfg Configuration) *InProcess {
-	log := logger.NewLogger(zap.NewRaw(), false)
+	log := providerLogger.NewFlagdLogger(cfg.Logger)
 
-	iSync, uri := makeSyncProvider(cfg, log)
+	service := &InProcess{
+		events:           make(chan of.Event, 5),
+		logger:           log,
+		listenerShutdown: make(chan interface{}),
+		retryGracePeriod: cfg.RetryGracePeriod,
+		lostSources:      map[string]struct{}{},
+		observer:         observer.OrNoop(cfg.Observer),
+		strictValidation: cfg.StrictValidation,
+		customOperators:  sortedKeys(cfg.CustomEvaluators),
+	}
+	service.gracePeriod = retry.NewGracePeriod(cfg.RetryGracePeriod, service.handleStale, service.handleGracePeriodExpiry)
+
+	if cfg.SnapshotPath != "" && cfg.OfflineFlagSource == "" && len(cfg.OfflineFlagSources) == 0 {
+		// a snapshot holds the flag configuration of a single sync source
+		if cfg.SyncURL == "" && len(syncSelectors(cfg)) > 1 {
+			log.Warn("flag snapshots are not supported with multiple selectors, ignoring snapshot " + cfg.SnapshotPath)
+		} else {
+			service.snapshot = &snapshot{path: cfg.SnapshotPath}
+			// flags of the snapshot are served until the sync source is available, hence attempts never end
+			cfg.RetryPolicy.Unlimited = true
+		}
+	}
 
-	// service specific metadata
+	iSync, sources, err := makeSyncProvider(cfg, log, service)
+	if err != nil {
+		log.Error(err.Error())
+	}
+
+	// service specific metadata. With multiple selectors, the evaluator adds the selector of each flag instead
 	var svcMetadata map[string]interface{}
-	if cfg.Selector != "" {
+	if selectors := syncSelectors(cfg); len(selectors) == 1 {
 		svcMetadata = make(map[string]interface{}, 1)
-		svcMetadata["scope"] = cfg.Selector
+		svcMetadata["scope"] = selectors[0]
 	}
 
 	flagStore := store.NewFlags()
-	flagStore.FlagSources = append(flagStore.FlagSources, uri)
-
-	jsonEvaluator := evaluator.NewJSON(log,
-		flagStore,
-		evaluator.WithEvaluator(
-			"fractional",
-			evaluator.NewFractional(log).Evaluate,
-		),
-		evaluator.WithEvaluator(
-			"starts_with",
-			evaluator.NewStringComparisonEvaluator(log).StartsWithEvaluation,
-		),
-		evaluator.WithEvaluator(
-			"ends_with",
-			evaluator.NewStringComparisonEvaluator(log).EndsWithEvaluation,
-		),
-		evaluator.WithEvaluator(
-			"sem_ver",
-			evaluator.NewSemVerComparison(log).SemVerEvaluation,
-		))
-
-	return &InProcess{
-		evaluator:        jsonEvaluator,
-		events:           make(chan of.Event, 5),
-		logger:           log,
-		listenerShutdown: make(chan interface{}),
-		serviceMetadata:  svcMetadata,
-		sync:             iSync,
+	sourceNames := make([]string, 0, len(sources))
+	for _, source := range sources {
+		flagStore.FlagSources = append(flagStore.FlagSources, source.Source)
+		flagStore.SourceMetadata[source.Source] = source
+		sourceNames = append(sourceNames, source.Source)
+	}
+
+	evaluatorOpts, evaluatorErr := evaluatorOptions(log, cfg.CustomEvaluators)
+	if evaluatorErr != nil {
+		log.Error(evaluatorErr.Error())
 	}
+
+	jsonEvaluator := evaluator.NewJSON(log, flagStore, evaluatorOpts...)
+
+	service.evaluator = jsonEvaluator
+	service.flagStore = flagStore
+	service.lastSync = make(map[string]time.Time, len(sources))
+	service.serviceMetadata = svcMetadata
+	service.sources = sourceNames
+	service.sync = iSync
+	service.initErr = errors.Join(err, evaluatorErr)
+
+	return service
 }
 
 func (i *InProcess) Init() error {
 	var ctx context.Context
 	ctx, i.syncEnd = context.WithCancel(context.Background())
 
+	if i.initErr != nil {
+		return i.initErr
+	}
+
 	err := i.sync.Init(ctx)
 	if err != nil {
 		return err
 	}
 
 	initOnce := parallel.Once{}
-	syncInitSuccess := make(chan interface{})
-	syncInitErr := make(chan error)
-
 	syncChan := make(chan sync.DataSync, 1)
 
 	// start data sync
-	go func() {
-		err := i.sync.Sync(ctx, syncChan)
-		if err != nil {
-			syncInitErr <- err
-		}
-	}()
+	go i.startSync(ctx, syncChan)
 
 	// start data sync listener and listen to listener shutdown hook
 	go func() {
+		// the provider is ready once each source delivered its flags
+		pending := make(map[string]bool, len(i.sources))
+		for _, source := range i.sources {
+			pending[source] = true
+		}
+
 		for {
 			select {
 			case data := <-syncChan:
-				// re-syncs are ignored as we only support single flag sync source
-				changes, _, err := i.evaluator.SetState(data)
+				validationErr := i.validate(data)
+				if validationErr != nil && i.strictValidation {
+					// keep the last valid flag configuration
+					i.events <- of.Event{
+						ProviderName: "flagd", EventType: of.ProviderError,
+						ProviderEventDetails: of.ProviderEventDetails{Message: validationErr.Error()}}
+					continue
+				}
+
+				previous := i.flagStore.GetAll()
+				changes, reSync, err := i.evaluator.SetState(data)
 				if err != nil {
+					// schema violations describe malformed configurations more precisely
+					if validationErr != nil {
+						err = validationErr
+					}
+
 					i.events <- of.Event{
 						ProviderName: "flagd", EventType: of.ProviderError,
 						ProviderEventDetails: of.ProviderEventDetails{Message: "Error from flag sync " + err.Error()}}
+				} else {
+					i.saveSnapshot(data)
+					i.observer.SyncUpdate(data.Source, len(changes))
+					i.lastSyncMtx.Lock()
+					i.lastSync[data.Source] = time.Now()
+					i.lastSyncMtx.Unlock()
+
+					// flags of the sync source replace those of the snapshot once a sync is applied
+					i.degraded.Store(false)
+				}
+
+				// flags removed from a source may be defined by sources of lower precedence, which need to re-sync
+				if len(i.sources) > 1 && (reSync || data.Type == sync.DELETE) {
+					go i.reSync(ctx, syncChan)
+				}
+
+				delete(pending, data.Source)
+				if len(pending) == 0 {
+					initOnce.Do(func() {
+						i.ready.Store(true)
+						i.events <- of.Event{ProviderName: "flagd", EventType: of.ProviderReady}
+					})
 				}
-				initOnce.Do(func() {
-					i.events <- of.Event{ProviderName: "flagd", EventType: of.ProviderReady}
-					syncInitSuccess <- nil
-				})
+
+				flagChanges, changeMetadata := i.describeChanges(previous, changes)
 				i.events <- of.Event{
 					ProviderName: "flagd", EventType: of.ProviderConfigChange,
-					ProviderEventDetails: of.ProviderEventDetails{Message: "New flag sync", FlagChanges: maps.Keys(changes)}}
+					ProviderEventDetails: of.ProviderEventDetails{
+						Message:     "New flag sync",
+						FlagChanges: flagChanges,
+						EventMetadata: map[string]interface{}{
+							"source":                data.Source,
+							flagchanges.MetadataKey: changeMetadata,
+						},
+					}}
 			case <-i.listenerShutdown:
 				i.logger.Info("Shutting down data sync listener")
 				return
@@ -130,16 +251,222 @@ func (i *InProcess) Init() error {
 		}
 	}()
 
-	// wait for initialization or error
-	select {
-	case <-syncInitSuccess:
+	return nil
+}
+
+// describeChanges returns the sorted keys of changed flags and the metadata of each change, based on the state change
+// notifications of the store and the flags before the change
+func (i *InProcess) describeChanges(previous map[string]model.Flag, notifications map[string]interface{}) (
+	[]string, map[string]interface{}) {
+	keys := maps.Keys(notifications)
+	sort.Strings(keys)
+
+	metadata := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		var changeType string
+		if notification, ok := notifications[key].(map[string]interface{}); ok {
+			changeType, _ = notification["type"].(string)
+		}
+
+		current, _ := i.flagStore.Get(key)
+		metadata[key] = flagchanges.Change(
+			flagchanges.TypeOf(changeType), previous[key].DefaultVariant, current.DefaultVariant)
+	}
+
+	return keys, metadata
+}
+
+// validate validates the flag configuration of a sync against the flagd flag definition schema. Violations are logged
+// unless validation is strict, as flagd applies the valid parts of a configuration
+func (i *InProcess) validate(data sync.DataSync) error {
+	if data.Type == sync.DELETE {
 		return nil
-	case err := <-syncInitErr:
-		return err
+	}
+
+	err := validateFlagConfiguration(data.Source, data.FlagData, i.customOperators)
+	if err != nil && !i.strictValidation {
+		i.logger.Warn(err.Error())
+	}
+
+	return err
+}
+
+// reSync requests all flags from all sources
+func (i *InProcess) reSync(ctx context.Context, syncChan chan sync.DataSync) {
+	if err := i.sync.ReSync(ctx, syncChan); err != nil {
+		i.logger.Warn(fmt.Sprintf("flag re-sync failed: %s", err.Error()))
+	}
+}
+
+// startSync starts the data sync. Sync sources are responsible to re-establish their connection, hence a sync only
+// returns once the context is done or the source failed permanently. This contains blocking calls, hence must be
+// called concurrently. A permanent failure emits an event with openfeature.ProviderError, unless the failure is
+// reported by the grace period.
+func (i *InProcess) startSync(ctx context.Context, syncChan chan sync.DataSync) {
+	err := i.sync.Sync(ctx, syncChan)
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+
+	i.logger.Warn(fmt.Sprintf("flag sync exited with error: %s", err.Error()))
+	if i.gracePeriod.IsLost() || i.degraded.Load() {
+		return
+	}
+
+	i.emit(ctx, of.Event{
+		ProviderName: "flagd", EventType: of.ProviderError,
+		ProviderEventDetails: of.ProviderEventDetails{Message: "Error from flag sync " + err.Error()}})
+}
+
+// onConnected emits an event with openfeature.ProviderReady once the lost connections of all sources are
+// re-established. The initial ready event is emitted with the first flag sync
+func (i *InProcess) onConnected(source string) {
+	i.observer.Connected(source)
+
+	i.lostSourcesMtx.Lock()
+	delete(i.lostSources, source)
+	restored := len(i.lostSources) == 0
+	i.lostSourcesMtx.Unlock()
+
+	if restored && i.gracePeriod.Restored() && i.ready.Load() {
+		i.events <- of.Event{ProviderName: "flagd", EventType: of.ProviderReady}
+	}
+}
+
+// onConnectionLost starts the grace period, during which the last known flag state is served
+func (i *InProcess) onConnectionLost(source string) {
+	i.observer.Disconnected(source)
+
+	i.lostSourcesMtx.Lock()
+	i.lostSources[source] = struct{}{}
+	i.lostSourcesMtx.Unlock()
+
+	if i.ready.Load() {
+		i.gracePeriod.Lost()
+	}
+}
+
+// onRetriesExhausted emits an event with openfeature.ProviderError once connection attempts are exhausted, unless
+// the lost connection is reported by the grace period or flags of the snapshot are served
+func (i *InProcess) onRetriesExhausted(_ string, err error) {
+	if i.gracePeriod.IsLost() || i.degraded.Load() {
+		return
+	}
+
+	if !i.ready.Load() && i.loadSnapshotOnce() {
+		return
+	}
+
+	message := "grpc connection establishment failed"
+	if err != nil {
+		message = fmt.Sprintf("%s: %s", message, err.Error())
+	}
+
+	i.events <- of.Event{
+		ProviderName: "flagd", EventType: of.ProviderError,
+		ProviderEventDetails: of.ProviderEventDetails{Message: message}}
+}
+
+// onRetry reports the retried connection attempt to the observer. If the sync source is not available at startup,
+// flags of the snapshot are served while attempts continue
+func (i *InProcess) onRetry(source string, delay time.Duration) {
+	i.observer.RetryAttempt(source, delay)
+
+	if !i.ready.Load() {
+		i.loadSnapshotOnce()
+	}
+}
+
+// handleStale emits an event with openfeature.ProviderStale once an established sync connection is lost
+func (i *InProcess) handleStale() {
+	i.logger.Warn(fmt.Sprintf("flag sync lost, serving last known flags for %s", i.retryGracePeriod))
+
+	i.events <- of.Event{
+		ProviderName: "flagd", EventType: of.ProviderStale,
+		ProviderEventDetails: of.ProviderEventDetails{Message: "connection to flag sync lost"}}
+}
+
+// handleGracePeriodExpiry emits an event with openfeature.ProviderError once the sync connection was not
+// re-established within the grace period
+func (i *InProcess) handleGracePeriodExpiry() {
+	i.events <- of.Event{
+		ProviderName: "flagd", EventType: of.ProviderError,
+		ProviderEventDetails: of.ProviderEventDetails{
+			Message: fmt.Sprintf("connection to flag sync not re-established within %s", i.retryGracePeriod)}}
+}
+
+// saveSnapshot persists a full flag configuration to the snapshot file, if configured
+func (i *InProcess) saveSnapshot(data sync.DataSync) {
+	if i.snapshot == nil || data.Type != sync.ALL {
+		return
+	}
+
+	if err := i.snapshot.save(data.FlagData); err != nil {
+		i.logger.Warn(fmt.Sprintf("failed to persist flag snapshot: %s", err.Error()))
+	}
+}
+
+// loadSnapshotOnce loads the snapshot with the first failed attempt of the sync source. Returns whether flags of the
+// snapshot are served
+func (i *InProcess) loadSnapshotOnce() bool {
+	i.snapshotOnce.Do(func() {
+		i.loadSnapshot()
+	})
+
+	return i.degraded.Load()
+}
+
+// loadSnapshot serves the flags of the snapshot file, if configured, and emits an event with
+// openfeature.ProviderStale. Flags of the snapshot are replaced by the first sync of the sync source, which emits an
+// event with openfeature.ProviderReady
+func (i *InProcess) loadSnapshot() bool {
+	if i.snapshot == nil {
+		return false
+	}
+
+	flagData, err := i.snapshot.load()
+	if err != nil {
+		i.logger.Warn(fmt.Sprintf("flag sync failed and no snapshot is available: %s", err.Error()))
+		return false
+	}
+
+	// as with flag syncs, only strict validation rejects snapshots
+	err = i.validate(sync.DataSync{FlagData: flagData, Source: i.snapshot.path, Type: sync.ALL})
+	if err != nil && i.strictValidation {
+		i.logger.Warn(fmt.Sprintf("flag sync failed and the snapshot is invalid: %s", err.Error()))
+		return false
+	}
+
+	// the snapshot is stored as the sync source, hence the first sync replaces all of its flags
+	_, _, err = i.evaluator.SetState(sync.DataSync{FlagData: flagData, Source: i.sources[0], Type: sync.ALL})
+	if err != nil {
+		i.logger.Warn(fmt.Sprintf("failed to load flag snapshot: %s", err.Error()))
+		return false
+	}
+
+	i.degraded.Store(true)
+	i.logger.Warn("flag sync failed, serving flags of snapshot " + i.snapshot.path)
+
+	i.events <- of.Event{
+		ProviderName: "flagd", EventType: of.ProviderStale,
+		ProviderEventDetails: of.ProviderEventDetails{
+			Message:       "flag sync failed, serving flags of snapshot " + i.snapshot.path,
+			EventMetadata: map[string]interface{}{"source": i.snapshot.path},
+		}}
+
+	return true
+}
+
+// emit sends the event unless the context is done
+func (i *InProcess) emit(ctx context.Context, event of.Event) {
+	select {
+	case i.events <- event:
+	case <-ctx.Done():
 	}
 }
 
 func (i *InProcess) Shutdown() {
+	i.gracePeriod.Stop()
 	i.syncEnd()
 	close(i.listenerShutdown)
 }
@@ -274,6 +601,39 @@ func (i *InProcess) ResolveObject(ctx context.Context, key string, defaultValue
 	}
 }
 
+// ResolveAll evaluates all enabled flags of the current flag configuration. Resolved values are typed by their flag
+// definition (bool, string, float64 or map[string]interface{})
+func (i *InProcess) ResolveAll(ctx context.Context, evalCtx map[string]interface{}) (
+	map[string]of.InterfaceResolutionDetail, error) {
+	values := i.evaluator.ResolveAllValues(ctx, "", evalCtx)
+
+	details := make(map[string]of.InterfaceResolutionDetail, len(values))
+	for _, value := range values {
+		if value.Metadata == nil {
+			value.Metadata = map[string]interface{}{}
+		}
+		i.appendMetadata(value.Metadata)
+
+		detail := of.InterfaceResolutionDetail{
+			Value: value.Value,
+			ProviderResolutionDetail: of.ProviderResolutionDetail{
+				Reason:       of.Reason(value.Reason),
+				Variant:      value.Variant,
+				FlagMetadata: value.Metadata,
+			},
+		}
+
+		if value.Error != nil {
+			detail.Value = nil
+			detail.ResolutionError = mapError(value.FlagKey, value.Error)
+		}
+
+		details[value.FlagKey] = detail
+	}
+
+	return details, nil
+}
+
 func (i *InProcess) EventChannel() <-chan of.Event {
 	return i.events
 }
@@ -285,29 +645,118 @@ func (i *InProcess) appendMetadata(evalMetadata map[string]interface{}) {
 	}
 }
 
-// makeSyncProvider is a helper to create sync.ISync and return the underlying uri used by it to the caller
-func makeSyncProvider(cfg Configuration, log *logger.Logger) (sync.ISync, string) {
+// makeSyncProvider is a helper to create sync.ISync and return the underlying sources used by it to the caller, in
+// the order of their precedence
+func makeSyncProvider(cfg Configuration, log *logger.Logger, listener connectionListener) (
+	sync.ISync, []store.SourceDetails, error) {
+	var offlineSources []string
 	if cfg.OfflineFlagSource != "" {
+		offlineSources = append(offlineSources, cfg.OfflineFlagSource)
+	}
+	offlineSources = append(offlineSources, cfg.OfflineFlagSources...)
+
+	if len(offlineSources) > 0 {
 		// file sync provider
-		log.Info("operating in in-process mode with offline flags sourced from " + cfg.OfflineFlagSource)
-		return &file.Sync{
-			URI:    cfg.OfflineFlagSource,
-			Logger: log,
-			Mux:    &parallel.RWMutex{},
-		}, cfg.OfflineFlagSource
+		files, err := expandOfflineSources(offlineSources)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		log.Info("operating in in-process mode with offline flags sourced from " + strings.Join(files, ", "))
+		return newFileSync(files, log), sourceDetails(files...), nil
+	}
+
+	if cfg.SyncURL != "" {
+		// http sync provider
+		log.Info("operating in in-process mode with flags polled from " + cfg.SyncURL)
+		return &httpSync{
+			headers:      headers.New(cfg.Headers, cfg.TokenSource),
+			listener:     listener,
+			logger:       log,
+			pollInterval: cfg.SyncPollInterval,
+			retryPolicy:  cfg.RetryPolicy,
+			tlsOptions:   tlsOptions(cfg),
+			uri:          cfg.SyncURL,
+		}, sourceDetails(cfg.SyncURL), nil
 	}
 
 	// grpc sync provider
 	uri := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
 	log.Info("operating in in-process mode with flags sourced from " + uri)
 
-	return &grpc.Sync{
-		CredentialBuilder: &credentials.CredentialBuilder{},
-		Logger:            log,
-		Secure:            cfg.TLSEnabled,
-		Selector:          cfg.Selector,
-		URI:               uri,
-	}, uri
+	selectors := syncSelectors(cfg)
+	if len(selectors) <= 1 {
+		var selector string
+		if len(selectors) == 1 {
+			selector = selectors[0]
+		}
+
+		return newGrpcSync(cfg, log, listener, uri, selector, uri),
+			[]store.SourceDetails{{Source: uri, Selector: selector}}, nil
+	}
+
+	// each selector is synced with its own stream, hence its flags are stored as a source of its own
+	syncs := make([]sync.ISync, 0, len(selectors))
+	sources := make([]store.SourceDetails, 0, len(selectors))
+	for _, selector := range selectors {
+		source := fmt.Sprintf("%s?selector=%s", uri, selector)
+		syncs = append(syncs, newGrpcSync(cfg, log, listener, uri, selector, source))
+		sources = append(sources, store.SourceDetails{Source: source, Selector: selector})
+	}
+
+	log.Info("syncing flags of selectors " + strings.Join(selectors, ", "))
+	return &multiSync{syncs: syncs}, sources, nil
+}
+
+func newGrpcSync(cfg Configuration, log *logger.Logger, listener connectionListener, uri string, selector string,
+	source string) *grpcSync {
+	return &grpcSync{
+		headers:     headers.New(cfg.Headers, cfg.TokenSource),
+		listener:    listener,
+		logger:      log,
+		retryPolicy: cfg.RetryPolicy,
+		secure:      cfg.TLSEnabled,
+		tlsOptions:  tlsOptions(cfg),
+		selector:    selector,
+		uri:         uri,
+		source:      source,
+	}
+}
+
+// syncSelectors returns the selectors of the configuration in the order of their precedence. Selectors appearing
+// multiple times are kept at their last position
+func syncSelectors(cfg Configuration) []string {
+	var selectors []string
+	if cfg.Selector != "" {
+		selectors = append(selectors, cfg.Selector)
+	}
+
+	for _, selector := range cfg.Selectors {
+		if selector != "" {
+			selectors = append(selectors, selector)
+		}
+	}
+
+	return dedupeKeepLast(selectors)
+}
+
+// sourceDetails returns the details of sources without selectors, as selectors only apply to the grpc sync
+func sourceDetails(sources ...string) []store.SourceDetails {
+	details := make([]store.SourceDetails, 0, len(sources))
+	for _, source := range sources {
+		details = append(details, store.SourceDetails{Source: source})
+	}
+
+	return details
+}
+
+func tlsOptions(cfg Configuration) tlsconfig.Options {
+	return tlsconfig.Options{
+		CertificatePath: cfg.CertificatePath,
+		ClientCertPath:  cfg.ClientCertPath,
+		ClientKeyPath:   cfg.ClientKeyPath,
+		ServerName:      cfg.ServerName,
+	}
 }
 
 // mapError is a helper to map evaluation errors to OF errors
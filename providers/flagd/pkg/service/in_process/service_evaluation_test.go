@@ -303,6 +303,49 @@ func TestErrorMapping(t *testing.T) {
 	}
 }
 
+func TestResolveAll(t *testing.T) {
+	inProcessService := InProcess{
+		evaluator: MockEvaluator{
+			allValues: []evaluator.AnyValue{
+				evaluator.NewAnyValue(true, "on", string(openfeature.StaticReason), "bool",
+					map[string]interface{}{}, nil),
+				evaluator.NewAnyValue(1.5, "half", string(openfeature.TargetingMatchReason), "float",
+					map[string]interface{}{}, nil),
+				evaluator.NewAnyValue(nil, "", string(openfeature.ErrorReason), "broken",
+					map[string]interface{}{}, errors.New(model.ParseErrorCode)),
+			},
+		},
+		serviceMetadata: map[string]interface{}{"scope": "app"},
+	}
+
+	details, err := inProcessService.ResolveAll(context.Background(), make(map[string]interface{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(details) != 3 {
+		t.Fatalf("expected 3 resolutions, got %d", len(details))
+	}
+
+	if details["bool"].Value != true || details["bool"].Variant != "on" ||
+		details["bool"].Reason != openfeature.StaticReason {
+		t.Errorf("unexpected boolean resolution %v", details["bool"])
+	}
+
+	if details["float"].Value != 1.5 || details["float"].Reason != openfeature.TargetingMatchReason {
+		t.Errorf("unexpected float resolution %v", details["float"])
+	}
+
+	if details["float"].FlagMetadata["scope"] != "app" {
+		t.Errorf("expected service metadata to be appended, got %v", details["float"].FlagMetadata)
+	}
+
+	if details["broken"].Error() == nil ||
+		!strings.HasPrefix(details["broken"].Error().Error(), string(openfeature.ParseErrorCode)) {
+		t.Errorf("expected parse error for broken flag, got %v", details["broken"].Error())
+	}
+}
+
 // commonValidator for tests
 func commonValidator(t *testing.T, test testDescription, value interface{}, details openfeature.ProviderResolutionDetail) {
 	if test.value != value {
@@ -328,11 +371,12 @@ func commonValidator(t *testing.T, test testDescription, value interface{}, deta
 
 // Mock Evaluator for testing
 type MockEvaluator struct {
-	value    interface{}
-	variant  string
-	reason   string
-	metadata map[string]interface{}
-	err      error
+	value     interface{}
+	variant   string
+	reason    string
+	metadata  map[string]interface{}
+	err       error
+	allValues []evaluator.AnyValue
 }
 
 func (m MockEvaluator) ResolveBooleanValue(ctx context.Context, reqID string, flagKey string, context map[string]any) (value bool, variant string, reason string, metadata map[string]interface{}, err error) {
@@ -366,6 +410,5 @@ func (m MockEvaluator) SetState(payload sync.DataSync) (map[string]interface{},
 }
 
 func (m MockEvaluator) ResolveAllValues(ctx context.Context, reqID string, context map[string]any) (values []evaluator.AnyValue) {
-	// ignored
-	return nil
+	return m.allValues
 }
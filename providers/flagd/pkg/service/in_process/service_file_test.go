@@ -51,3 +51,141 @@ func TestInProcessOfflineMode(t *testing.T) {
 		t.Fatal("Expected scope to be present, but got none")
 	}
 }
+
+func TestInProcessOfflineModeMultipleSources(t *testing.T) {
+	// given - flag files of two teams, where the second file overrides a flag of the first
+	dir := t.TempDir()
+	teamA := filepath.Join(dir, "a.json")
+	teamB := filepath.Join(dir, "b.yaml")
+
+	err := os.WriteFile(teamA, []byte(flagRsp), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(teamB, []byte(`
+flags:
+  myBoolFlag:
+    state: ENABLED
+    variants:
+      "on": true
+      "off": false
+    defaultVariant: "off"
+  teamBFlag:
+    state: ENABLED
+    variants:
+      "on": true
+      "off": false
+    defaultVariant: "on"
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// when
+	service := NewInProcessService(Configuration{OfflineFlagSources: []string{dir}})
+
+	err = service.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer service.Shutdown()
+
+	// then - provider is ready once both files are loaded
+	awaitEvent(t, service, of.ProviderReady)
+	go func() {
+		for range service.EventChannel() {
+		}
+	}()
+
+	detail := service.ResolveBoolean(context.Background(), "myBoolFlag", true, make(map[string]interface{}))
+	if detail.Value {
+		t.Fatal("Expected flag of the later file to take precedence")
+	}
+
+	detail = service.ResolveBoolean(context.Background(), "teamBFlag", false, make(map[string]interface{}))
+	if !detail.Value {
+		t.Fatal("Expected flag of the later file to be available")
+	}
+
+	// when - the overriding flag is removed from the later file
+	err = os.WriteFile(teamB, []byte(`
+flags:
+  teamBFlag:
+    state: ENABLED
+    variants:
+      "on": true
+      "off": false
+    defaultVariant: "on"
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// then - the definition of the earlier file applies again
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		detail = service.ResolveBoolean(context.Background(), "myBoolFlag", false, make(map[string]interface{}))
+		if detail.Value {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("Expected flag of the earlier file to apply once removed from the later file")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestExpandOfflineSources(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.json", "a.yaml", "c.txt", "d.yml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := expandOfflineSources([]string{
+		filepath.Join(dir, "b.json"),
+		filepath.Join(dir, "*.y*ml"),
+		dir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		filepath.Join(dir, "a.yaml"),
+		filepath.Join(dir, "b.json"),
+		filepath.Join(dir, "d.yml"),
+	}
+
+	if len(files) != len(expected) {
+		t.Fatalf("expected files %v, got %v", expected, files)
+	}
+
+	for i := range expected {
+		if files[i] != expected[i] {
+			t.Fatalf("expected files %v, got %v", expected, files)
+		}
+	}
+
+	if _, err := expandOfflineSources([]string{filepath.Join(dir, "*.none")}); err == nil {
+		t.Fatal("expected error if no flag files match")
+	}
+}
+
+// awaitEvent drains events of the service until an event of the given type is received
+func awaitEvent(t *testing.T, service *InProcess, eventType of.EventType) {
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event := <-service.EventChannel():
+			if event.EventType == eventType {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("Provider did not emit %s within acceptable timeframe", eventType)
+		}
+	}
+}
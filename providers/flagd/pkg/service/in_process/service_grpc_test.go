@@ -5,6 +5,7 @@ import (
 	v1 "buf.build/gen/go/open-feature/flagd/protocolbuffers/go/flagd/sync/v1"
 	"context"
 	"fmt"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/retry"
 	"github.com/open-feature/go-sdk/openfeature"
 	"google.golang.org/grpc"
 	"log"
@@ -112,6 +113,205 @@ func TestInProcessProviderEvaluation(t *testing.T) {
 	if scope != detail.FlagMetadata["scope"] {
 		t.Fatalf("Wrong scope value. Expected %s, but got %s", scope, detail.FlagMetadata["scope"])
 	}
+
+	// loaded flags expose their source and selector
+	definition, ok := inProcessService.FlagDefinition("myBoolFlag")
+	if !ok {
+		t.Fatal("Expected flag definition, but got none")
+	}
+
+	if definition.Source != fmt.Sprintf("%s:%d", host, port) || definition.Selector != scope {
+		t.Fatalf("Wrong flag source. Got source %s with selector %s", definition.Source, definition.Selector)
+	}
+}
+
+func TestInProcessProviderSyncRetry(t *testing.T) {
+	// given - a sync server which is not yet available
+	host := "localhost"
+	port := 8091
+
+	inProcessService := NewInProcessService(Configuration{
+		Host:        host,
+		Port:        port,
+		TLSEnabled:  false,
+		RetryPolicy: retry.Policy{BaseDelay: 100 * time.Millisecond, MaxAttempts: 1, Unlimited: true},
+	})
+
+	// when
+	err := inProcessService.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inProcessService.Shutdown()
+
+	// then - initial sync failure is reported
+	select {
+	case event := <-inProcessService.events:
+		if event.EventType != openfeature.ProviderError {
+			t.Fatalf("expected event %s, got %s", openfeature.ProviderError, event.EventType)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Provider did not report the sync failure within an acceptable timeframe")
+	}
+
+	// when - sync server becomes available
+	listen, err := net.Listen("tcp", fmt.Sprintf("%s:%d", host, port))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		serve(&bufferedServer{
+			listener: listen,
+			mockResponses: []*v1.SyncFlagsResponse{
+				{
+					FlagConfiguration: flagRsp,
+				},
+			},
+		})
+	}()
+
+	// then - provider becomes ready with the retried sync
+	select {
+	case event := <-inProcessService.events:
+		if event.EventType != openfeature.ProviderReady {
+			t.Fatalf("expected event %s, got %s", openfeature.ProviderReady, event.EventType)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Provider did not recover within an acceptable timeframe")
+	}
+}
+
+func TestInProcessProviderStale(t *testing.T) {
+	// given - a sync server which ends the sync stream after a delay
+	host := "localhost"
+	port := 8092
+
+	listen, err := net.Listen("tcp", fmt.Sprintf("%s:%d", host, port))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		serve(&bufferedServer{
+			listener: listen,
+			mockResponses: []*v1.SyncFlagsResponse{
+				{
+					FlagConfiguration: flagRsp,
+				},
+			},
+		})
+	}()
+
+	inProcessService := NewInProcessService(Configuration{
+		Host:             host,
+		Port:             port,
+		TLSEnabled:       false,
+		RetryPolicy:      retry.Policy{BaseDelay: 100 * time.Millisecond, MaxAttempts: 5},
+		RetryGracePeriod: time.Minute,
+	})
+
+	// when
+	err = inProcessService.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inProcessService.Shutdown()
+
+	// then - the lost stream is reported as stale and last known flags are served, until the stream is re-established
+	expected := []openfeature.EventType{
+		openfeature.ProviderReady,
+		openfeature.ProviderConfigChange,
+		openfeature.ProviderStale,
+		openfeature.ProviderReady,
+	}
+
+	for _, eventType := range expected {
+		select {
+		case event := <-inProcessService.events:
+			if event.EventType != eventType {
+				t.Fatalf("expected event %s, got %s", eventType, event.EventType)
+			}
+		case <-time.After(4 * time.Second):
+			t.Fatalf("Provider did not emit %s within an acceptable timeframe", eventType)
+		}
+
+		if eventType == openfeature.ProviderStale {
+			detail := inProcessService.ResolveBoolean(
+				context.Background(), "myBoolFlag", false, make(map[string]interface{}))
+			if !detail.Value {
+				t.Fatal("Expected last known flag value to be served while stale")
+			}
+		}
+	}
+}
+
+func TestInProcessProviderSelectors(t *testing.T) {
+	// given - a sync server serving a base flag set and a team flag set overriding a flag of the base
+	host := "localhost"
+	port := 8093
+
+	listen, err := net.Listen("tcp", fmt.Sprintf("%s:%d", host, port))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		serve(&bufferedServer{
+			listener: listen,
+			selectorResponses: map[string][]*v1.SyncFlagsResponse{
+				"base": {{FlagConfiguration: `{"flags": {
+					"myBoolFlag": {"state": "ENABLED", "variants": {"on": true, "off": false}, "defaultVariant": "on"},
+					"baseFlag": {"state": "ENABLED", "variants": {"on": true, "off": false}, "defaultVariant": "on"}}}`}},
+				"team": {{FlagConfiguration: `{"flags": {
+					"myBoolFlag": {"state": "ENABLED", "variants": {"on": true, "off": false}, "defaultVariant": "off"}}}`}},
+			},
+		})
+	}()
+
+	inProcessService := NewInProcessService(Configuration{
+		Host:      host,
+		Port:      port,
+		Selectors: []string{"base", "team"},
+	})
+
+	// when
+	err = inProcessService.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inProcessService.Shutdown()
+
+	// then - provider is ready once both selectors synced
+	timeout := time.After(2 * time.Second)
+	for ready := false; !ready; {
+		select {
+		case event := <-inProcessService.events:
+			ready = event.EventType == openfeature.ProviderReady
+		case <-timeout:
+			t.Fatal("Provider initialization did not complete within acceptable timeframe")
+		}
+	}
+
+	// flags of the later selector take precedence, and resolutions carry the selector of their flag
+	detail := inProcessService.ResolveBoolean(context.Background(), "myBoolFlag", true, make(map[string]interface{}))
+	if detail.Value || detail.FlagMetadata["scope"] != "team" {
+		t.Fatalf("Expected false of selector team, got %v of selector %v", detail.Value, detail.FlagMetadata["scope"])
+	}
+
+	detail = inProcessService.ResolveBoolean(context.Background(), "baseFlag", false, make(map[string]interface{}))
+	if !detail.Value || detail.FlagMetadata["scope"] != "base" {
+		t.Fatalf("Expected true of selector base, got %v of selector %v", detail.Value, detail.FlagMetadata["scope"])
+	}
+
+	definition, ok := inProcessService.FlagDefinition("myBoolFlag")
+	if !ok {
+		t.Fatal("Expected flag definition, but got none")
+	}
+
+	if definition.Source != fmt.Sprintf("%s:%d?selector=team", host, port) || definition.Selector != "team" {
+		t.Fatalf("Wrong flag source. Got source %s with selector %s", definition.Source, definition.Selector)
+	}
 }
 
 // bufferedServer - a mock grpc service backed by buffered connection
@@ -120,10 +320,17 @@ type bufferedServer struct {
 	mockResponses         []*v1.SyncFlagsResponse
 	fetchAllFlagsResponse *v1.FetchAllFlagsResponse
 	fetchAllFlagsError    error
+	// selectorResponses, if set, replace the mock responses with the responses of the requested selector
+	selectorResponses map[string][]*v1.SyncFlagsResponse
 }
 
-func (b *bufferedServer) SyncFlags(_ *v1.SyncFlagsRequest, stream syncv1grpc.FlagSyncService_SyncFlagsServer) error {
-	for _, response := range b.mockResponses {
+func (b *bufferedServer) SyncFlags(req *v1.SyncFlagsRequest, stream syncv1grpc.FlagSyncService_SyncFlagsServer) error {
+	responses := b.mockResponses
+	if b.selectorResponses != nil {
+		responses = b.selectorResponses[req.GetSelector()]
+	}
+
+	for _, response := range responses {
 		err := stream.Send(response)
 		if err != nil {
 			fmt.Printf("Error with stream: %s", err.Error())
@@ -0,0 +1,462 @@
+package process
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	parallel "sync"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/observer"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/retry"
+	of "github.com/open-feature/go-sdk/openfeature"
+)
+
+// flagServer serves a flag configuration with an ETag and records conditional requests
+type flagServer struct {
+	mtx           parallel.Mutex
+	config        string
+	etag          string
+	failures      int
+	notModified   int
+	authorization string
+}
+
+func (f *flagServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.authorization = r.Header.Get("Authorization")
+
+	if f.failures > 0 {
+		f.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	if r.Header.Get("If-None-Match") == f.etag {
+		f.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("ETag", f.etag)
+	_, _ = w.Write([]byte(f.config))
+}
+
+func (f *flagServer) update(config string, etag string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.config = config
+	f.etag = etag
+}
+
+func TestInProcessHTTPSync(t *testing.T) {
+	// given
+	flags := &flagServer{config: flagRsp, etag: `"v1"`}
+	server := httptest.NewServer(flags)
+	defer server.Close()
+
+	service := NewInProcessService(Configuration{
+		SyncURL:          server.URL,
+		SyncPollInterval: 50 * time.Millisecond,
+		TokenSource: func(context.Context) (string, error) {
+			return "token", nil
+		},
+	})
+
+	// when
+	err := service.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer service.Shutdown()
+
+	// then - provider is ready with the polled flags
+	awaitEvent(t, service, of.ProviderReady)
+	go func() {
+		for range service.EventChannel() {
+		}
+	}()
+
+	detail := service.ResolveBoolean(context.Background(), "myBoolFlag", false, make(map[string]interface{}))
+	if !detail.Value {
+		t.Fatal("Expected true, but got false")
+	}
+
+	// when - the configuration is modified
+	time.Sleep(200 * time.Millisecond)
+	flags.update(strings.Replace(flagRsp, `"defaultVariant": "on"`, `"defaultVariant": "off"`, 1), `"v2"`)
+
+	// then - the change is applied
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		detail = service.ResolveBoolean(context.Background(), "myBoolFlag", true, make(map[string]interface{}))
+		if !detail.Value {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("Expected modified configuration to be applied")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	flags.mtx.Lock()
+	defer flags.mtx.Unlock()
+
+	if flags.notModified == 0 {
+		t.Error("Expected unchanged configurations to be polled with conditional requests")
+	}
+
+	if flags.authorization != "Bearer token" {
+		t.Errorf("Expected bearer token to be sent, got %q", flags.authorization)
+	}
+}
+
+func TestInProcessHTTPSyncRetry(t *testing.T) {
+	// given - a flag server which is unavailable for the first polls
+	flags := &flagServer{config: flagRsp, etag: `"v1"`, failures: 3}
+	server := httptest.NewServer(flags)
+	defer server.Close()
+
+	recorder := &connectionObserver{}
+	service := NewInProcessService(Configuration{
+		SyncURL:     server.URL,
+		RetryPolicy: retry.Policy{BaseDelay: 50 * time.Millisecond, MaxAttempts: 1, Unlimited: true},
+		Observer:    recorder,
+	})
+
+	// when
+	err := service.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer service.Shutdown()
+
+	// then - failures are reported, and the provider becomes ready once polls succeed
+	awaitEvent(t, service, of.ProviderError)
+	awaitEvent(t, service, of.ProviderReady)
+
+	recorder.mtx.Lock()
+	defer recorder.mtx.Unlock()
+
+	if recorder.retries != 3 || recorder.syncUpdates != 1 {
+		t.Errorf("expected 3 retries and 1 sync update, got %d retries and %d sync updates",
+			recorder.retries, recorder.syncUpdates)
+	}
+}
+
+// connectionObserver counts retries and sync notifications
+type connectionObserver struct {
+	observer.Noop
+	mtx                  parallel.Mutex
+	retries, syncUpdates int
+}
+
+func (c *connectionObserver) RetryAttempt(string, time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.retries++
+}
+
+func (c *connectionObserver) SyncUpdate(string, int) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.syncUpdates++
+}
+
+func TestInProcessHTTPSyncSnapshot(t *testing.T) {
+	// given - a snapshot of a previous run, and a flag server which is unavailable at startup
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot.json")
+	previous := strings.Replace(flagRsp, `"defaultVariant": "on"`, `"defaultVariant": "off"`, 1)
+	if err := os.WriteFile(snapshotPath, []byte(previous), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	flags := &flagServer{config: flagRsp, etag: `"v1"`, failures: 1000}
+	server := httptest.NewServer(flags)
+	defer server.Close()
+
+	service := NewInProcessService(Configuration{
+		SyncURL:      server.URL,
+		RetryPolicy:  retry.Policy{BaseDelay: 50 * time.Millisecond, MaxAttempts: 1, Unlimited: true},
+		SnapshotPath: snapshotPath,
+	})
+
+	// when
+	err := service.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer service.Shutdown()
+
+	// then - flags of the snapshot are served in the stale state
+	select {
+	case event := <-service.EventChannel():
+		if event.EventType != of.ProviderStale {
+			t.Fatalf("expected event %s, got %s", of.ProviderStale, event.EventType)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Provider did not fall back to the snapshot within an acceptable timeframe")
+	}
+
+	detail := service.ResolveBoolean(context.Background(), "myBoolFlag", true, make(map[string]interface{}))
+	if detail.Value {
+		t.Fatal("Expected flag value of the snapshot, but got true")
+	}
+
+	// when - the flag server becomes available
+	flags.mtx.Lock()
+	flags.failures = 0
+	flags.mtx.Unlock()
+
+	// then - the provider becomes ready with live flags, which are persisted to the snapshot
+	awaitEvent(t, service, of.ProviderReady)
+
+	detail = service.ResolveBoolean(context.Background(), "myBoolFlag", false, make(map[string]interface{}))
+	if !detail.Value {
+		t.Fatal("Expected live flag value, but got false")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		snapshot, err := os.ReadFile(snapshotPath)
+		if err == nil && string(snapshot) == flagRsp {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("Expected live flags to be persisted to the snapshot")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestInProcessHTTPSyncSnapshotInvalidSync(t *testing.T) {
+	// given - a snapshot of a previous run, and a flag server which is unavailable at startup
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot.json")
+	previous := strings.Replace(flagRsp, `"defaultVariant": "on"`, `"defaultVariant": "off"`, 1)
+	if err := os.WriteFile(snapshotPath, []byte(previous), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	flags := &flagServer{config: "{invalid", etag: `"v1"`, failures: 1000}
+	server := httptest.NewServer(flags)
+	defer server.Close()
+
+	service := NewInProcessService(Configuration{
+		SyncURL:      server.URL,
+		RetryPolicy:  retry.Policy{BaseDelay: 50 * time.Millisecond, MaxAttempts: 1, Unlimited: true},
+		SnapshotPath: snapshotPath,
+	})
+
+	err := service.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer service.Shutdown()
+
+	awaitEvent(t, service, of.ProviderStale)
+
+	// when - the flag server becomes available, but serves an invalid flag configuration
+	flags.mtx.Lock()
+	flags.failures = 0
+	flags.mtx.Unlock()
+
+	// then - the sync is rejected, and flags of the snapshot are still served in the degraded state
+	awaitEvent(t, service, of.ProviderError)
+
+	if !service.degraded.Load() {
+		t.Fatal("Expected the provider to remain degraded after a rejected sync")
+	}
+
+	detail := service.ResolveBoolean(context.Background(), "myBoolFlag", true, make(map[string]interface{}))
+	if detail.Value {
+		t.Fatal("Expected flag value of the snapshot, but got true")
+	}
+}
+
+func TestInProcessHTTPSyncSnapshotLimitedRetries(t *testing.T) {
+	// given - a snapshot of a previous run, a flag server which is unavailable beyond the attempts of the retry
+	// policy, and a policy with limited attempts as by default
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot.json")
+	previous := strings.Replace(flagRsp, `"defaultVariant": "on"`, `"defaultVariant": "off"`, 1)
+	if err := os.WriteFile(snapshotPath, []byte(previous), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	flags := &flagServer{config: flagRsp, etag: `"v1"`, failures: 3}
+	server := httptest.NewServer(flags)
+	defer server.Close()
+
+	service := NewInProcessService(Configuration{
+		SyncURL:      server.URL,
+		RetryPolicy:  retry.Policy{BaseDelay: 500 * time.Millisecond, MaxDelay: 500 * time.Millisecond, MaxAttempts: 1},
+		SnapshotPath: snapshotPath,
+	})
+
+	// when
+	err := service.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer service.Shutdown()
+
+	// then - flags of the snapshot are served with the first failed attempt
+	select {
+	case event := <-service.EventChannel():
+		if event.EventType != of.ProviderStale {
+			t.Fatalf("expected event %s, got %s", of.ProviderStale, event.EventType)
+		}
+	case <-time.After(400 * time.Millisecond):
+		t.Fatal("Provider did not fall back to the snapshot with the first failed attempt")
+	}
+
+	// then - attempts continue once exhausted, and the provider becomes ready with live flags
+	awaitEvent(t, service, of.ProviderReady)
+
+	detail := service.ResolveBoolean(context.Background(), "myBoolFlag", false, make(map[string]interface{}))
+	if !detail.Value {
+		t.Fatal("Expected live flag value, but got false")
+	}
+}
+
+func TestInProcessConfigChangeMetadata(t *testing.T) {
+	// given
+	flags := &flagServer{config: flagRsp, etag: `"v1"`}
+	server := httptest.NewServer(flags)
+	defer server.Close()
+
+	service := NewInProcessService(Configuration{
+		SyncURL:          server.URL,
+		SyncPollInterval: 50 * time.Millisecond,
+	})
+
+	err := service.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer service.Shutdown()
+
+	// then - the initial sync adds the flag
+	awaitEvent(t, service, of.ProviderReady)
+	event := awaitConfigChange(t, service)
+
+	expected := map[string]interface{}{
+		"myBoolFlag": map[string]interface{}{"type": "added", "newDefaultVariant": "on"},
+	}
+	if !reflect.DeepEqual(event.EventMetadata["changes"], expected) {
+		t.Fatalf("expected change metadata %v, got %v", expected, event.EventMetadata["changes"])
+	}
+
+	// when - the default variant is changed and a flag is added
+	flags.update(`{
+		"flags": {
+			"myBoolFlag": {
+				"state": "ENABLED",
+				"variants": {"on": true, "off": false},
+				"defaultVariant": "off"
+			},
+			"newFlag": {
+				"state": "ENABLED",
+				"variants": {"on": true, "off": false},
+				"defaultVariant": "on"
+			}
+		}
+	}`, `"v2"`)
+
+	// then
+	event = awaitConfigChange(t, service)
+
+	if !reflect.DeepEqual(event.FlagChanges, []string{"myBoolFlag", "newFlag"}) {
+		t.Fatalf("expected sorted flag changes, got %v", event.FlagChanges)
+	}
+
+	expected = map[string]interface{}{
+		"myBoolFlag": map[string]interface{}{"type": "updated", "oldDefaultVariant": "on", "newDefaultVariant": "off"},
+		"newFlag":    map[string]interface{}{"type": "added", "newDefaultVariant": "on"},
+	}
+	if !reflect.DeepEqual(event.EventMetadata["changes"], expected) {
+		t.Fatalf("expected change metadata %v, got %v", expected, event.EventMetadata["changes"])
+	}
+
+	// when - a flag is removed
+	flags.update(flagRsp, `"v3"`)
+
+	// then
+	event = awaitConfigChange(t, service)
+
+	expected = map[string]interface{}{
+		"myBoolFlag": map[string]interface{}{"type": "updated", "oldDefaultVariant": "off", "newDefaultVariant": "on"},
+		"newFlag":    map[string]interface{}{"type": "removed", "oldDefaultVariant": "on"},
+	}
+	if !reflect.DeepEqual(event.EventMetadata["changes"], expected) {
+		t.Fatalf("expected change metadata %v, got %v", expected, event.EventMetadata["changes"])
+	}
+}
+
+func TestInProcessStrictValidation(t *testing.T) {
+	// given
+	flags := &flagServer{config: flagRsp, etag: `"v1"`}
+	server := httptest.NewServer(flags)
+	defer server.Close()
+
+	service := NewInProcessService(Configuration{
+		SyncURL:          server.URL,
+		SyncPollInterval: 50 * time.Millisecond,
+		StrictValidation: true,
+	})
+
+	err := service.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer service.Shutdown()
+
+	awaitEvent(t, service, of.ProviderReady)
+
+	// when - the configuration is replaced by an invalid one
+	flags.update(strings.Replace(flagRsp, `"state": "ENABLED"`, `"state": "ON"`, 1), `"v2"`)
+
+	// then - the configuration is rejected with its violations
+	var rejection of.Event
+	timeout := time.After(2 * time.Second)
+	for rejection.EventType != of.ProviderError {
+		select {
+		case rejection = <-service.EventChannel():
+		case <-timeout:
+			t.Fatal("Provider did not reject the invalid configuration within an acceptable timeframe")
+		}
+	}
+
+	if !strings.Contains(rejection.Message, server.URL) || !strings.Contains(rejection.Message, "$.flags.myBoolFlag.state") {
+		t.Errorf("expected violation of the flag state of %s, got %s", server.URL, rejection.Message)
+	}
+
+	// then - the last valid configuration is kept
+	detail := service.ResolveBoolean(context.Background(), "myBoolFlag", false, make(map[string]interface{}))
+	if !detail.Value || detail.Error() != nil {
+		t.Errorf("expected the last valid configuration to be evaluated, got %v", detail)
+	}
+}
+
+func awaitConfigChange(t *testing.T, service *InProcess) of.Event {
+	select {
+	case event := <-service.EventChannel():
+		if event.EventType != of.ProviderConfigChange {
+			t.Fatalf("expected event %s, got %s", of.ProviderConfigChange, event.EventType)
+		}
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("Provider did not emit a configuration change within an acceptable timeframe")
+	}
+
+	return of.Event{}
+}
@@ -0,0 +1,34 @@
+package process
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/atomicfile"
+)
+
+// snapshot persists the last flag configuration received from a live sync source to a file, which serves as a
+// fallback if the sync source is not available at startup
+type snapshot struct {
+	path string
+}
+
+// save replaces the snapshot file with the flag configuration, hence readers never observe a partially written
+// snapshot
+func (s *snapshot) save(flagData string) error {
+	if err := atomicfile.Write(s.path, []byte(flagData)); err != nil {
+		return fmt.Errorf("error writing snapshot file: %w", err)
+	}
+
+	return nil
+}
+
+// load reads the flag configuration of the snapshot file
+func (s *snapshot) load() (string, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return "", fmt.Errorf("error reading snapshot file %s: %w", s.path, err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,158 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	parallel "sync"
+
+	schema "github.com/open-feature/flagd-schemas/json"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// ValidationError reports a flag configuration of a source, which does not conform to the flagd flag definition schema
+type ValidationError struct {
+	// Source is the file or URI the configuration was loaded from
+	Source string
+	// Violations of the schema, at least one
+	Violations []Violation
+}
+
+// Violation is a violation of the flagd flag definition schema
+type Violation struct {
+	// Path is the JSON path of the violating element, such as $.flags.myFlag.state
+	Path string
+	// Reason describes the violation
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	violations := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		violations = append(violations, fmt.Sprintf("%s: %s", v.Path, v.Reason))
+	}
+
+	return fmt.Sprintf("invalid flag configuration of %s: %s", e.Source, strings.Join(violations, "; "))
+}
+
+// combinatorErrors are the types of errors of schema combinators, which are only reported without other violations
+var combinatorErrors = map[string]bool{"number_one_of": true, "number_all_of": true, "number_any_of": true}
+
+var (
+	// flagSchemas are the compiled schemas by their custom operators
+	flagSchemas   = map[string]*gojsonschema.Schema{}
+	flagSchemaMtx parallel.Mutex
+)
+
+// compiledFlagSchema compiles the flag definition schema and its targeting schema, extended with the custom
+// operators, once for each set of custom operators
+func compiledFlagSchema(customOperators []string) (*gojsonschema.Schema, error) {
+	key := strings.Join(customOperators, "\x00")
+
+	flagSchemaMtx.Lock()
+	defer flagSchemaMtx.Unlock()
+
+	if compiled, ok := flagSchemas[key]; ok {
+		return compiled, nil
+	}
+
+	targetingSchema, err := extendTargetingSchema(customOperators)
+	if err != nil {
+		return nil, err
+	}
+
+	loader := gojsonschema.NewSchemaLoader()
+	if err := loader.AddSchemas(gojsonschema.NewStringLoader(targetingSchema)); err != nil {
+		return nil, fmt.Errorf("error adding targeting schema: %w", err)
+	}
+
+	compiled, err := loader.Compile(gojsonschema.NewStringLoader(schema.FlagSchema))
+	if err != nil {
+		return nil, err
+	}
+
+	flagSchemas[key] = compiled
+	return compiled, nil
+}
+
+// extendTargetingSchema adds a rule of the custom operators to the rules of the targeting schema, as rules of the
+// schema do not permit unknown operators. Arguments of custom operators are not validated
+func extendTargetingSchema(customOperators []string) (string, error) {
+	if len(customOperators) == 0 {
+		return schema.TargetingSchema, nil
+	}
+
+	var targeting map[string]interface{}
+	if err := json.Unmarshal([]byte(schema.TargetingSchema), &targeting); err != nil {
+		return "", fmt.Errorf("error parsing targeting schema: %w", err)
+	}
+
+	defs, _ := targeting["$defs"].(map[string]interface{})
+	anyRule, _ := defs["anyRule"].(map[string]interface{})
+	rules, ok := anyRule["anyOf"].([]interface{})
+	if !ok {
+		return "", fmt.Errorf("error extending targeting schema: rules not found")
+	}
+
+	properties := make(map[string]interface{}, len(customOperators))
+	for _, operator := range customOperators {
+		properties[operator] = map[string]interface{}{}
+	}
+
+	defs["customRule"] = map[string]interface{}{
+		"title":                "Custom Operation",
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties":           properties,
+	}
+	anyRule["anyOf"] = append(rules, map[string]interface{}{"$ref": "#/$defs/customRule"})
+
+	extended, err := json.Marshal(targeting)
+	if err != nil {
+		return "", fmt.Errorf("error extending targeting schema: %w", err)
+	}
+
+	return string(extended), nil
+}
+
+// validateFlagConfiguration validates the flag configuration of the source against the flagd flag definition schema,
+// with the custom operators permitted in targeting rules. Configurations which are not valid JSON are reported as a
+// violation of the root element
+func validateFlagConfiguration(source string, flagData string, customOperators []string) error {
+	compiled, err := compiledFlagSchema(customOperators)
+	if err != nil {
+		return err
+	}
+
+	result, err := compiled.Validate(gojsonschema.NewStringLoader(flagData))
+	if err != nil {
+		return &ValidationError{Source: source, Violations: []Violation{{Path: "$", Reason: err.Error()}}}
+	}
+
+	if result.Valid() {
+		return nil
+	}
+
+	var violations, combinators []Violation
+	for _, resultErr := range result.Errors() {
+		path := "$"
+		field := resultErr.Field()
+		if field != gojsonschema.STRING_ROOT_SCHEMA_PROPERTY {
+			path += "." + field
+		}
+
+		violation := Violation{Path: path, Reason: strings.TrimPrefix(resultErr.Description(), field+" ")}
+		if combinatorErrors[resultErr.Type()] {
+			combinators = append(combinators, violation)
+		} else {
+			violations = append(violations, violation)
+		}
+	}
+
+	// failed schema combinators are a consequence of the violations of their schemas
+	if len(violations) == 0 {
+		violations = combinators
+	}
+
+	return &ValidationError{Source: source, Violations: violations}
+}
@@ -0,0 +1,72 @@
+package process
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateFlagConfiguration(t *testing.T) {
+	tests := map[string]struct {
+		flagData        string
+		customOperators []string
+		violations      []Violation
+	}{
+		"valid configuration": {
+			flagData: flagRsp,
+		},
+		"invalid state": {
+			flagData: `{"flags": {"myBoolFlag": {
+				"state": "ON", "variants": {"on": true, "off": false}, "defaultVariant": "on"}}}`,
+			violations: []Violation{{
+				Path:   "$.flags.myBoolFlag.state",
+				Reason: `must be one of the following: "ENABLED", "DISABLED"`,
+			}},
+		},
+		"missing property": {
+			flagData:   `{"flags": {"myBoolFlag": {"state": "ENABLED", "variants": {"on": true, "off": false}}}}`,
+			violations: []Violation{{Path: "$.flags.myBoolFlag", Reason: "defaultVariant is required"}},
+		},
+		"registered custom operator": {
+			flagData:        cidrFlags,
+			customOperators: []string{"cidr"},
+		},
+		"unregistered custom operator": {
+			flagData: cidrFlags,
+			violations: []Violation{{
+				Path:   "$.flags.internalUser.targeting.if.0",
+				Reason: "Additional property cidr is not allowed",
+			}},
+		},
+		"malformed json": {
+			flagData:   `{"flags": {`,
+			violations: []Violation{{Path: "$", Reason: "unexpected EOF"}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateFlagConfiguration("flags.json", test.flagData, test.customOperators)
+
+			if test.violations == nil {
+				if err != nil {
+					t.Fatalf("expected valid configuration, got %s", err.Error())
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+
+			if validationErr.Source != "flags.json" {
+				t.Errorf("expected source flags.json, got %s", validationErr.Source)
+			}
+
+			if !reflect.DeepEqual(validationErr.Violations, test.violations) {
+				t.Errorf("expected violations %v, got %v", test.violations, validationErr.Violations)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	schemaConnectV1 "buf.build/gen/go/open-feature/flagd/connectrpc/go/flagd/evaluation/v1/evaluationv1connect"
+	v1 "buf.build/gen/go/open-feature/flagd/protocolbuffers/go/flagd/evaluation/v1"
+	"connectrpc.com/connect"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/cache"
+	of "github.com/open-feature/go-sdk/openfeature"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// evaluationServer is a flagd evaluation service recording the calls it serves
+type evaluationServer struct {
+	schemaConnectV1.UnimplementedServiceHandler
+
+	mtx         sync.Mutex
+	protocol    string
+	httpVersion int
+	encoding    string
+	deadline    bool
+	delay       time.Duration
+}
+
+func (e *evaluationServer) ResolveBoolean(ctx context.Context, req *connect.Request[v1.ResolveBooleanRequest]) (
+	*connect.Response[v1.ResolveBooleanResponse], error) {
+	e.mtx.Lock()
+	e.protocol = req.Peer().Protocol
+	e.encoding = req.Header().Get("Content-Encoding") + req.Header().Get("Grpc-Encoding")
+	_, e.deadline = ctx.Deadline()
+	delay := e.delay
+	e.mtx.Unlock()
+
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+		return nil, connect.NewError(connect.CodeDeadlineExceeded, ctx.Err())
+	}
+
+	return connect.NewResponse(&v1.ResolveBooleanResponse{Value: true, Reason: string(of.StaticReason)}), nil
+}
+
+// startEvaluationServer serves the evaluation service over HTTP/1.1 and h2c
+func startEvaluationServer(t *testing.T, evaluation *evaluationServer) Configuration {
+	path, handler := schemaConnectV1.NewServiceHandler(evaluation)
+
+	mux := http.NewServeMux()
+	mux.Handle(path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		evaluation.mtx.Lock()
+		evaluation.httpVersion = r.ProtoMajor
+		evaluation.mtx.Unlock()
+
+		handler.ServeHTTP(w, r)
+	}))
+
+	server := httptest.NewServer(h2c.NewHandler(mux, &http2.Server{}))
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return Configuration{Host: serverURL.Hostname(), Port: uint16(port)}
+}
+
+func TestClientProtocols(t *testing.T) {
+	tests := map[string]struct {
+		protocol    Protocol
+		compression string
+		h2c         bool
+
+		expectedProtocol    string
+		expectedHTTPVersion int
+		expectedEncoding    string
+	}{
+		"default": {
+			expectedProtocol:    connect.ProtocolConnect,
+			expectedHTTPVersion: 1,
+		},
+		"connect with h2c": {
+			protocol:            ProtocolConnect,
+			h2c:                 true,
+			expectedProtocol:    connect.ProtocolConnect,
+			expectedHTTPVersion: 2,
+		},
+		"grpc with h2c": {
+			protocol:            ProtocolGRPC,
+			h2c:                 true,
+			expectedProtocol:    connect.ProtocolGRPC,
+			expectedHTTPVersion: 2,
+		},
+		"grpc-web": {
+			protocol:            ProtocolGRPCWeb,
+			expectedProtocol:    connect.ProtocolGRPCWeb,
+			expectedHTTPVersion: 1,
+		},
+		"connect with gzip": {
+			compression:         CompressionGzip,
+			expectedProtocol:    connect.ProtocolConnect,
+			expectedHTTPVersion: 1,
+			expectedEncoding:    "gzip",
+		},
+		"grpc with gzip": {
+			protocol:            ProtocolGRPC,
+			compression:         CompressionGzip,
+			h2c:                 true,
+			expectedProtocol:    connect.ProtocolGRPC,
+			expectedHTTPVersion: 2,
+			expectedEncoding:    "gzip",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			evaluation := &evaluationServer{}
+			cfg := startEvaluationServer(t, evaluation)
+			cfg.Protocol = test.protocol
+			cfg.Compression = test.compression
+			cfg.H2C = test.h2c
+
+			service := newTestService(t, cfg)
+
+			detail := service.ResolveBoolean(context.Background(), "flag", false, map[string]interface{}{})
+			if detail.Error() != nil {
+				t.Fatalf("expected evaluation to succeed, got %v", detail.Error())
+			}
+
+			if !detail.Value {
+				t.Error("expected value true, got false")
+			}
+
+			evaluation.mtx.Lock()
+			defer evaluation.mtx.Unlock()
+
+			if evaluation.protocol != test.expectedProtocol {
+				t.Errorf("expected protocol %s, got %s", test.expectedProtocol, evaluation.protocol)
+			}
+
+			if evaluation.httpVersion != test.expectedHTTPVersion {
+				t.Errorf("expected HTTP/%d, got HTTP/%d", test.expectedHTTPVersion, evaluation.httpVersion)
+			}
+
+			if evaluation.encoding != test.expectedEncoding {
+				t.Errorf("expected encoding %q, got %q", test.expectedEncoding, evaluation.encoding)
+			}
+		})
+	}
+}
+
+func TestClientCallTimeout(t *testing.T) {
+	// given - a server slower than the call timeout
+	evaluation := &evaluationServer{delay: time.Second}
+	cfg := startEvaluationServer(t, evaluation)
+	cfg.CallTimeout = 50 * time.Millisecond
+
+	service := newTestService(t, cfg)
+
+	// when - the evaluation has no deadline
+	start := time.Now()
+	detail := service.ResolveBoolean(context.Background(), "flag", false, map[string]interface{}{})
+
+	// then - the call timeout applies
+	if detail.Error() == nil || detail.Value {
+		t.Fatalf("expected the evaluation to time out, got %v", detail)
+	}
+
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("expected the evaluation to be bounded by the call timeout, took %s", elapsed)
+	}
+
+	// when - the evaluation has a deadline of its own
+	evaluation.mtx.Lock()
+	evaluation.delay = 100 * time.Millisecond
+	evaluation.mtx.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	detail = service.ResolveBoolean(ctx, "flag", false, map[string]interface{}{})
+
+	// then - the deadline of the context applies
+	if detail.Error() != nil || !detail.Value {
+		t.Fatalf("expected the evaluation to succeed within the deadline of its context, got %v", detail)
+	}
+
+	evaluation.mtx.Lock()
+	defer evaluation.mtx.Unlock()
+
+	if !evaluation.deadline {
+		t.Error("expected the deadline to be propagated to the server")
+	}
+}
+
+func TestClientConfigurationErrors(t *testing.T) {
+	tests := map[string]Configuration{
+		"unknown protocol":    {Host: "localhost", Port: 8013, Protocol: "http"},
+		"unknown compression": {Host: "localhost", Port: 8013, Compression: "br"},
+		"h2c with tls":        {Host: "localhost", Port: 8013, TLSEnabled: true, H2C: true},
+	}
+
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := newClient(cfg); err == nil {
+				t.Error("expected client creation to fail")
+			}
+		})
+	}
+}
+
+// newTestService creates a service with a client of the configuration, without starting the event stream
+func newTestService(t *testing.T, cfg Configuration) *Service {
+	client, err := newClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Service{
+		cache:  cache.NewCacheService(cache.DisabledValue, 0, 0, log),
+		cfg:    cfg,
+		logger: log,
+		client: client,
+	}
+}
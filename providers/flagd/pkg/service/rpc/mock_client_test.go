@@ -13,6 +13,7 @@ type MockClient struct {
 	floatResponse   v1.ResolveFloatResponse
 	intResponse     v1.ResolveIntResponse
 	objResponse     v1.ResolveObjectResponse
+	allResponse     v1.ResolveAllResponse
 
 	error error
 }
@@ -54,6 +55,6 @@ func (m *MockClient) EventStream(context.Context, *connect.Request[v1.EventStrea
 
 func (m *MockClient) ResolveAll(context.Context, *connect.Request[v1.ResolveAllRequest]) (*connect.Response[v1.ResolveAllResponse], error) {
 	return &connect.Response[v1.ResolveAllResponse]{
-		Msg: &v1.ResolveAllResponse{},
+		Msg: &m.allResponse,
 	}, m.error
 }
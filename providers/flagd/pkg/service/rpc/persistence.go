@@ -0,0 +1,261 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	flagdModels "github.com/open-feature/flagd/core/pkg/model"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/atomicfile"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/logger"
+	of "github.com/open-feature/go-sdk/openfeature"
+	"golang.org/x/net/context"
+)
+
+// ReasonPersisted is the reason of cached resolutions loaded from the cache file of a prior run
+const ReasonPersisted = "PERSISTED"
+
+const (
+	booleanType = "boolean"
+	stringType  = "string"
+	intType     = "integer"
+	floatType   = "float"
+	objectType  = "object"
+)
+
+// cachePersistence tracks the resolutions loaded from the cache file
+type cachePersistence struct {
+	mtx sync.Mutex
+	// loaded holds the flag keys of resolutions loaded from the cache file, until they are invalidated
+	loaded map[string]struct{}
+}
+
+// persistedCache is the content of the cache file
+type persistedCache struct {
+	Resolutions map[string]persistedResolution `json:"resolutions"`
+}
+
+// persistedResolution is a static resolution of the cache file
+type persistedResolution struct {
+	Type     string                 `json:"type"`
+	Value    json.RawMessage        `json:"value"`
+	Variant  string                 `json:"variant,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// cachedReason is the reason of a resolution served from the cache. Resolutions of a prior run keep their reason
+func cachedReason(reason of.Reason) of.Reason {
+	if reason == ReasonPersisted {
+		return ReasonPersisted
+	}
+
+	return ReasonCached
+}
+
+// loadPersistedCache adds the resolutions of the cache file to the cache. They are served with ReasonPersisted until
+// the first configuration change
+func (s *Service) loadPersistedCache() {
+	if s.persistence == nil || !s.cache.IsEnabled() {
+		return
+	}
+
+	data, err := os.ReadFile(s.cfg.CachePersistencePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			s.logger.V(logger.Debug).Info("no cache file at " + s.cfg.CachePersistencePath)
+		} else {
+			s.logger.Error(err, "failed to read cache file")
+		}
+		return
+	}
+
+	var persisted persistedCache
+	if err := json.Unmarshal(data, &persisted); err != nil {
+		s.logger.Error(err, fmt.Sprintf("ignoring invalid cache file %s", s.cfg.CachePersistencePath))
+		return
+	}
+
+	s.persistence.mtx.Lock()
+	defer s.persistence.mtx.Unlock()
+
+	s.persistence.loaded = make(map[string]struct{}, len(persisted.Resolutions))
+	for flagKey, resolution := range persisted.Resolutions {
+		detail, err := resolution.detail()
+		if err != nil {
+			s.logger.V(logger.Debug).Info(fmt.Sprintf("ignoring cached resolution of flag %s: %s", flagKey, err.Error()))
+			continue
+		}
+
+		s.cache.GetCache().Add(flagKey, detail)
+		s.persistence.loaded[flagKey] = struct{}{}
+	}
+
+	s.logger.V(logger.Info).Info(fmt.Sprintf("loaded %d cached resolutions from %s",
+		len(s.persistence.loaded), s.cfg.CachePersistencePath))
+}
+
+// invalidatePersistedCache removes the resolutions of the cache file from the cache, as well as the cache file
+func (s *Service) invalidatePersistedCache() {
+	if s.persistence == nil {
+		return
+	}
+
+	s.persistence.mtx.Lock()
+	defer s.persistence.mtx.Unlock()
+
+	if s.persistence.loaded == nil {
+		return
+	}
+
+	for flagKey := range s.persistence.loaded {
+		if value, ok := s.cache.GetCache().Get(flagKey); ok && providerDetailOf(value).Reason == ReasonPersisted {
+			s.cache.GetCache().Remove(flagKey)
+		}
+	}
+	s.persistence.loaded = nil
+
+	if err := os.Remove(s.cfg.CachePersistencePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		s.logger.Error(err, "failed to remove invalidated cache file")
+	}
+}
+
+// persistCache writes the static resolutions of the cache to the cache file. Resolutions loaded from the cache file
+// are not written again, as flags may have changed while flagd was not reachable. Disabled caches are not persisted,
+// as they were purged
+func (s *Service) persistCache() {
+	if s.persistence == nil || !s.cache.IsEnabled() {
+		return
+	}
+
+	persisted := persistedCache{Resolutions: map[string]persistedResolution{}}
+	for _, cacheKey := range s.cache.GetCache().Keys() {
+		value, ok := s.cache.GetCache().Get(cacheKey)
+		if !ok {
+			continue
+		}
+
+		if providerDetailOf(value).Reason != flagdModels.StaticReason {
+			continue
+		}
+
+		resolution, err := persistedResolutionOf(value)
+		if err != nil {
+			s.logger.V(logger.Debug).Info(fmt.Sprintf("not persisting resolution of flag %s: %s", cacheKey, err.Error()))
+			continue
+		}
+		persisted.Resolutions[cacheKey] = resolution
+	}
+
+	data, err := json.Marshal(persisted)
+	if err != nil {
+		s.logger.Error(err, "failed to encode cache file")
+		return
+	}
+
+	if err := atomicfile.Write(s.cfg.CachePersistencePath, data); err != nil {
+		s.logger.Error(err, "failed to write cache file")
+	}
+}
+
+// persistCachePeriodically persists the cache in the configured interval until the context is done
+func (s *Service) persistCachePeriodically(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.CachePersistenceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.persistCache()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// providerDetailOf returns the provider details, such as reason and variant, of a cached resolution
+func providerDetailOf(value interface{}) of.ProviderResolutionDetail {
+	switch detail := value.(type) {
+	case of.BoolResolutionDetail:
+		return detail.ProviderResolutionDetail
+	case of.StringResolutionDetail:
+		return detail.ProviderResolutionDetail
+	case of.IntResolutionDetail:
+		return detail.ProviderResolutionDetail
+	case of.FloatResolutionDetail:
+		return detail.ProviderResolutionDetail
+	case of.InterfaceResolutionDetail:
+		return detail.ProviderResolutionDetail
+	default:
+		return of.ProviderResolutionDetail{}
+	}
+}
+
+// persistedResolutionOf converts a cached resolution to its persisted form
+func persistedResolutionOf(value interface{}) (persistedResolution, error) {
+	var resolution persistedResolution
+	var detail of.ProviderResolutionDetail
+	var resolved interface{}
+
+	switch d := value.(type) {
+	case of.BoolResolutionDetail:
+		resolution.Type, resolved, detail = booleanType, d.Value, d.ProviderResolutionDetail
+	case of.StringResolutionDetail:
+		resolution.Type, resolved, detail = stringType, d.Value, d.ProviderResolutionDetail
+	case of.IntResolutionDetail:
+		resolution.Type, resolved, detail = intType, d.Value, d.ProviderResolutionDetail
+	case of.FloatResolutionDetail:
+		resolution.Type, resolved, detail = floatType, d.Value, d.ProviderResolutionDetail
+	case of.InterfaceResolutionDetail:
+		resolution.Type, resolved, detail = objectType, d.Value, d.ProviderResolutionDetail
+	default:
+		return resolution, fmt.Errorf("unsupported resolution %T", value)
+	}
+
+	var err error
+	resolution.Value, err = json.Marshal(resolved)
+	if err != nil {
+		return resolution, err
+	}
+
+	resolution.Variant = detail.Variant
+	resolution.Metadata = detail.FlagMetadata
+
+	return resolution, nil
+}
+
+// detail converts the persisted resolution to a cached resolution with ReasonPersisted
+func (r persistedResolution) detail() (interface{}, error) {
+	detail := of.ProviderResolutionDetail{
+		Reason:       ReasonPersisted,
+		Variant:      r.Variant,
+		FlagMetadata: r.Metadata,
+	}
+
+	switch r.Type {
+	case booleanType:
+		var value bool
+		err := json.Unmarshal(r.Value, &value)
+		return of.BoolResolutionDetail{Value: value, ProviderResolutionDetail: detail}, err
+	case stringType:
+		var value string
+		err := json.Unmarshal(r.Value, &value)
+		return of.StringResolutionDetail{Value: value, ProviderResolutionDetail: detail}, err
+	case intType:
+		var value int64
+		err := json.Unmarshal(r.Value, &value)
+		return of.IntResolutionDetail{Value: value, ProviderResolutionDetail: detail}, err
+	case floatType:
+		var value float64
+		err := json.Unmarshal(r.Value, &value)
+		return of.FloatResolutionDetail{Value: value, ProviderResolutionDetail: detail}, err
+	case objectType:
+		var value interface{}
+		err := json.Unmarshal(r.Value, &value)
+		return of.InterfaceResolutionDetail{Value: value, ProviderResolutionDetail: detail}, err
+	default:
+		return nil, fmt.Errorf("unsupported type %q", r.Type)
+	}
+}
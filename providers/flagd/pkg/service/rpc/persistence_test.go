@@ -0,0 +1,119 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "buf.build/gen/go/open-feature/flagd/protocolbuffers/go/flagd/evaluation/v1"
+	flagdService "github.com/open-feature/flagd/core/pkg/service"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/cache"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/retry"
+	of "github.com/open-feature/go-sdk/openfeature"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestPersistentCache(t *testing.T) {
+	evaluation := &evaluationServer{}
+	cfg := startEvaluationServer(t, evaluation)
+	cfg.CachePersistencePath = filepath.Join(t.TempDir(), "cache", "flagd-cache.json")
+
+	// given - a run caching a static resolution
+	previous := NewService(cfg, cache.NewCacheService(cache.InMemValue, 10, 0, log), log, retry.Policy{})
+
+	var err error
+	previous.client, err = newClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	detail := previous.ResolveBoolean(context.Background(), "flag", false, map[string]interface{}{})
+	if detail.Error() != nil || detail.Reason != of.StaticReason {
+		t.Fatalf("expected a static resolution, got %v", detail)
+	}
+
+	// when - the run shuts down
+	previous.Shutdown()
+
+	if _, err := os.Stat(cfg.CachePersistencePath); err != nil {
+		t.Fatalf("expected the cache to be persisted at shutdown: %v", err)
+	}
+
+	// then - the next run serves the persisted resolution before connecting to flagd
+	service := NewService(cfg, cache.NewCacheService(cache.InMemValue, 10, 0, log), log, retry.Policy{})
+	service.loadPersistedCache()
+
+	detail = service.ResolveBoolean(context.Background(), "flag", false, map[string]interface{}{})
+	if detail.Error() != nil || !detail.Value {
+		t.Fatalf("expected the persisted resolution, got %v", detail)
+	}
+
+	if detail.Reason != ReasonPersisted {
+		t.Errorf("expected reason %s, got %s", ReasonPersisted, detail.Reason)
+	}
+
+	// when - the configuration changes
+	data, err := structpb.NewStruct(map[string]interface{}{
+		"flags": map[string]interface{}{"otherFlag": map[string]interface{}{"type": "update"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service.handleConfigurationChangeEvent(&v1.EventStreamResponse{
+		Type: string(flagdService.ConfigurationChange),
+		Data: data,
+	})
+	<-service.EventChannel()
+
+	// then - persisted resolutions and the cache file are invalidated
+	detail = service.ResolveBoolean(context.Background(), "flag", false, map[string]interface{}{})
+	if detail.Reason == ReasonPersisted {
+		t.Error("expected persisted resolutions to be invalidated by the configuration change")
+	}
+
+	if _, err := os.Stat(cfg.CachePersistencePath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the cache file to be removed, got %v", err)
+	}
+}
+
+func TestPersistentCacheIsNotPersistedAgain(t *testing.T) {
+	evaluation := &evaluationServer{}
+	cfg := startEvaluationServer(t, evaluation)
+	cfg.CachePersistencePath = filepath.Join(t.TempDir(), "flagd-cache.json")
+
+	// given - a run caching a static resolution
+	previous := NewService(cfg, cache.NewCacheService(cache.InMemValue, 10, 0, log), log, retry.Policy{})
+
+	var err error
+	previous.client, err = newClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	detail := previous.ResolveBoolean(context.Background(), "flag", false, map[string]interface{}{})
+	if detail.Error() != nil || detail.Reason != of.StaticReason {
+		t.Fatalf("expected a static resolution, got %v", detail)
+	}
+	previous.Shutdown()
+
+	// when - the next run loads the persisted resolution and shuts down without resolving it from flagd
+	service := NewService(cfg, cache.NewCacheService(cache.InMemValue, 10, 0, log), log, retry.Policy{})
+	service.loadPersistedCache()
+
+	detail = service.ResolveBoolean(context.Background(), "flag", false, map[string]interface{}{})
+	if detail.Reason != ReasonPersisted {
+		t.Fatalf("expected reason %s, got %s", ReasonPersisted, detail.Reason)
+	}
+	service.Shutdown()
+
+	// then - the persisted resolution is not written again
+	next := NewService(cfg, cache.NewCacheService(cache.InMemValue, 10, 0, log), log, retry.Policy{})
+	next.loadPersistedCache()
+
+	if _, ok := next.cache.GetCache().Get("flag"); ok {
+		t.Error("expected the resolution of a prior run not to be persisted again")
+	}
+}
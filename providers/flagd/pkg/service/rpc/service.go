@@ -1,13 +1,16 @@
 package rpc
 
 import (
+	"crypto/sha256"
 	"crypto/tls"
-	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
-	"os"
+	"sort"
+	"strings"
 	"time"
 
 	schemaConnectV1 "buf.build/gen/go/open-feature/flagd/connectrpc/go/flagd/evaluation/v1/evaluationv1connect"
@@ -18,10 +21,16 @@ import (
 	flagdModels "github.com/open-feature/flagd/core/pkg/model"
 	flagdService "github.com/open-feature/flagd/core/pkg/service"
 	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/cache"
+	flagchanges "github.com/open-feature/go-sdk-contrib/providers/flagd/internal/changes"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/headers"
 	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/logger"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/observer"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/retry"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/tlsconfig"
 	"github.com/open-feature/go-sdk/openfeature"
 	of "github.com/open-feature/go-sdk/openfeature"
 	"golang.org/x/net/context"
+	"golang.org/x/net/http2"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
@@ -32,13 +41,45 @@ const (
 
 var ErrClientNotReady = of.NewProviderNotReadyResolutionError(ClientNotReadyMsg)
 
+// Protocol is the wire protocol of the client
+type Protocol string
+
+const (
+	ProtocolConnect Protocol = "connect"
+	ProtocolGRPC    Protocol = "grpc"
+	ProtocolGRPCWeb Protocol = "grpcweb"
+)
+
+// CompressionGzip compresses requests with gzip
+const CompressionGzip = "gzip"
+
 type Configuration struct {
-	Port            uint16
-	Host            string
-	CertificatePath string
-	SocketPath      string
-	TLSEnabled      bool
-	OtelInterceptor bool
+	Port              uint16
+	Host              string
+	CertificatePath   string
+	ClientCertPath    string
+	ClientKeyPath     string
+	ServerName        string
+	Headers           map[string]string
+	TokenSource       headers.TokenSource
+	SocketPath        string
+	TLSEnabled        bool
+	OtelInterceptor   bool
+	ContextAwareCache bool
+	RetryGracePeriod  time.Duration
+	Observer          observer.Observer
+	// Protocol is the wire protocol, defaults to the connect protocol
+	Protocol Protocol
+	// Compression is the compression of requests, requests are not compressed by default
+	Compression string
+	// H2C uses HTTP/2 over cleartext connections, as required by gRPC without TLS
+	H2C bool
+	// CallTimeout is the deadline of calls without a deadline of their context, no deadline applies if zero
+	CallTimeout time.Duration
+	// CachePersistencePath is the file static resolutions of the cache are persisted to, and loaded from at Init
+	CachePersistencePath string
+	// CachePersistenceInterval is the interval of persisting the cache, which is only persisted at Shutdown if zero
+	CachePersistenceInterval time.Duration
 }
 
 // Service handles the client side  interface for the flagd server
@@ -47,25 +88,47 @@ type Service struct {
 	cfg          Configuration
 	events       chan of.Event
 	logger       logr.Logger
-	retryCounter retryCounter
+	retryCounter *retry.Counter
+	gracePeriod  *retry.GracePeriod
+
+	// connected is set once the event stream signals readiness and reset once the stream is lost
+	connected bool
 
 	client     schemaConnectV1.ServiceClient
 	cancelHook context.CancelFunc
+
+	// persistence is set if the cache is persisted
+	persistence *cachePersistence
 }
 
-func NewService(cfg Configuration, cache *cache.Service, logger logr.Logger, retries int) *Service {
+func NewService(cfg Configuration, cache *cache.Service, logger logr.Logger, retryPolicy retry.Policy) *Service {
 	logger.Info("operating in rpc mode with flags sourced from " + fmt.Sprintf("%s:%d", cfg.Host, cfg.Port))
-	return &Service{
+	service := &Service{
 		cache:        cache,
 		cfg:          cfg,
 		events:       make(chan of.Event, 1),
 		logger:       logger,
-		retryCounter: newRetryCounter(retries),
+		retryCounter: retry.NewCounter(retryPolicy),
 	}
+
+	service.gracePeriod = retry.NewGracePeriod(cfg.RetryGracePeriod, service.handleStale, service.handleGracePeriodExpiry)
+
+	if cfg.CachePersistencePath != "" {
+		service.persistence = &cachePersistence{}
+	}
+
+	cache.OnExpiry(func() {
+		service.observer().CacheExpiry()
+	})
+
+	return service
 }
 
 const ConnectionError = "connection not made"
 
+// cacheKeySeparator separates the flag key from the evaluation context hash of context aware cache keys
+const cacheKeySeparator = "#"
+
 type resolutionRequestConstraints interface {
 	schemaV1.ResolveBooleanRequest | schemaV1.ResolveStringRequest | schemaV1.ResolveIntRequest |
 		schemaV1.ResolveFloatRequest | schemaV1.ResolveObjectRequest
@@ -83,6 +146,9 @@ func (s *Service) Init() error {
 		return err
 	}
 
+	s.observer().CacheStatus(s.cache.IsEnabled())
+	s.loadPersistedCache()
+
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	s.cancelHook = cancelFunc
 
@@ -90,6 +156,10 @@ func (s *Service) Init() error {
 		s.startEventStream(ctx)
 	}()
 
+	if s.persistence != nil && s.cfg.CachePersistenceInterval > 0 {
+		go s.persistCachePeriodically(ctx)
+	}
+
 	return nil
 }
 
@@ -97,20 +167,22 @@ func (s *Service) Shutdown() {
 	if s.cancelHook != nil {
 		s.cancelHook()
 	}
+
+	s.gracePeriod.Stop()
+	s.persistCache()
+	s.cache.Close()
 }
 
 // ResolveBoolean handles the flag evaluation response from the flagd ResolveBoolean rpc
 func (s *Service) ResolveBoolean(ctx context.Context, key string, defaultValue bool,
 	evalCtx map[string]interface{}) of.BoolResolutionDetail {
 
-	if s.cache.IsEnabled() {
-		fromCache, ok := s.cache.GetCache().Get(key)
+	fromCache, ok := s.getFromCache(key, evalCtx)
+	if ok {
+		fromCacheResDetail, ok := fromCache.(openfeature.BoolResolutionDetail)
 		if ok {
-			fromCacheResDetail, ok := fromCache.(openfeature.BoolResolutionDetail)
-			if ok {
-				fromCacheResDetail.Reason = ReasonCached
-				return fromCacheResDetail
-			}
+			fromCacheResDetail.Reason = cachedReason(fromCacheResDetail.Reason)
+			return fromCacheResDetail
 		}
 	}
 
@@ -150,9 +222,7 @@ func (s *Service) ResolveBoolean(ctx context.Context, key string, defaultValue b
 		},
 	}
 
-	if s.cache.IsEnabled() && detail.Reason == flagdModels.StaticReason {
-		s.cache.GetCache().Add(key, detail)
-	}
+	s.addToCache(key, evalCtx, detail.Reason, detail)
 
 	return detail
 }
@@ -161,14 +231,12 @@ func (s *Service) ResolveBoolean(ctx context.Context, key string, defaultValue b
 func (s *Service) ResolveString(ctx context.Context, key string, defaultValue string,
 	evalCtx map[string]interface{}) of.StringResolutionDetail {
 
-	if s.cache.IsEnabled() {
-		fromCache, ok := s.cache.GetCache().Get(key)
+	fromCache, ok := s.getFromCache(key, evalCtx)
+	if ok {
+		fromCacheResDetail, ok := fromCache.(openfeature.StringResolutionDetail)
 		if ok {
-			fromCacheResDetail, ok := fromCache.(openfeature.StringResolutionDetail)
-			if ok {
-				fromCacheResDetail.Reason = ReasonCached
-				return fromCacheResDetail
-			}
+			fromCacheResDetail.Reason = cachedReason(fromCacheResDetail.Reason)
+			return fromCacheResDetail
 		}
 	}
 
@@ -208,9 +276,7 @@ func (s *Service) ResolveString(ctx context.Context, key string, defaultValue st
 		},
 	}
 
-	if s.cache.IsEnabled() && detail.Reason == flagdModels.StaticReason {
-		s.cache.GetCache().Add(key, detail)
-	}
+	s.addToCache(key, evalCtx, detail.Reason, detail)
 
 	return detail
 }
@@ -219,14 +285,12 @@ func (s *Service) ResolveString(ctx context.Context, key string, defaultValue st
 func (s *Service) ResolveFloat(ctx context.Context, key string, defaultValue float64,
 	evalCtx map[string]interface{}) of.FloatResolutionDetail {
 
-	if s.cache.IsEnabled() {
-		fromCache, ok := s.cache.GetCache().Get(key)
+	fromCache, ok := s.getFromCache(key, evalCtx)
+	if ok {
+		fromCacheResDetail, ok := fromCache.(openfeature.FloatResolutionDetail)
 		if ok {
-			fromCacheResDetail, ok := fromCache.(openfeature.FloatResolutionDetail)
-			if ok {
-				fromCacheResDetail.Reason = ReasonCached
-				return fromCacheResDetail
-			}
+			fromCacheResDetail.Reason = cachedReason(fromCacheResDetail.Reason)
+			return fromCacheResDetail
 		}
 	}
 
@@ -266,9 +330,7 @@ func (s *Service) ResolveFloat(ctx context.Context, key string, defaultValue flo
 		},
 	}
 
-	if s.cache.IsEnabled() && detail.Reason == flagdModels.StaticReason {
-		s.cache.GetCache().Add(key, detail)
-	}
+	s.addToCache(key, evalCtx, detail.Reason, detail)
 
 	return detail
 }
@@ -277,14 +339,12 @@ func (s *Service) ResolveFloat(ctx context.Context, key string, defaultValue flo
 func (s *Service) ResolveInt(ctx context.Context, key string, defaultValue int64,
 	evalCtx map[string]interface{}) of.IntResolutionDetail {
 
-	if s.cache.IsEnabled() {
-		fromCache, ok := s.cache.GetCache().Get(key)
+	fromCache, ok := s.getFromCache(key, evalCtx)
+	if ok {
+		fromCacheResDetail, ok := fromCache.(openfeature.IntResolutionDetail)
 		if ok {
-			fromCacheResDetail, ok := fromCache.(openfeature.IntResolutionDetail)
-			if ok {
-				fromCacheResDetail.Reason = ReasonCached
-				return fromCacheResDetail
-			}
+			fromCacheResDetail.Reason = cachedReason(fromCacheResDetail.Reason)
+			return fromCacheResDetail
 		}
 	}
 
@@ -324,9 +384,7 @@ func (s *Service) ResolveInt(ctx context.Context, key string, defaultValue int64
 		},
 	}
 
-	if s.cache.IsEnabled() && detail.Reason == flagdModels.StaticReason {
-		s.cache.GetCache().Add(key, detail)
-	}
+	s.addToCache(key, evalCtx, detail.Reason, detail)
 
 	return detail
 }
@@ -334,14 +392,12 @@ func (s *Service) ResolveInt(ctx context.Context, key string, defaultValue int64
 // ResolveObject handles the flag evaluation response from the  flagd interface ResolveObject rpc
 func (s *Service) ResolveObject(ctx context.Context, key string, defaultValue interface{},
 	evalCtx map[string]interface{}) of.InterfaceResolutionDetail {
-	if s.cache.IsEnabled() {
-		fromCache, ok := s.cache.GetCache().Get(key)
+	fromCache, ok := s.getFromCache(key, evalCtx)
+	if ok {
+		fromCacheResDetail, ok := fromCache.(openfeature.InterfaceResolutionDetail)
 		if ok {
-			fromCacheResDetail, ok := fromCache.(openfeature.InterfaceResolutionDetail)
-			if ok {
-				fromCacheResDetail.Reason = ReasonCached
-				return fromCacheResDetail
-			}
+			fromCacheResDetail.Reason = cachedReason(fromCacheResDetail.Reason)
+			return fromCacheResDetail
 		}
 	}
 
@@ -381,13 +437,172 @@ func (s *Service) ResolveObject(ctx context.Context, key string, defaultValue in
 		},
 	}
 
-	if s.cache.IsEnabled() && detail.Reason == flagdModels.StaticReason {
-		s.cache.GetCache().Add(key, detail)
-	}
+	s.addToCache(key, evalCtx, detail.Reason, detail)
 
 	return detail
 }
 
+// ResolveAll handles the bulk evaluation response from the flagd interface ResolveAll rpc. Resolved values are typed
+// by their flag definition (bool, string, float64 or map[string]interface{}). The ResolveAll rpc does not provide flag
+// metadata, hence resolutions carry no flag metadata and do not populate the cache, which serves single flag
+// resolutions with their metadata.
+func (s *Service) ResolveAll(ctx context.Context, evalCtx map[string]interface{}) (
+	map[string]of.InterfaceResolutionDetail, error) {
+
+	if !s.isInitialised() {
+		return nil, ErrClientNotReady
+	}
+
+	evalCtxF, err := structpb.NewStruct(evalCtx)
+	if err != nil {
+		s.logger.Error(err, "struct from evaluation context")
+		return nil, openfeature.NewParseErrorResolutionError(err.Error())
+	}
+
+	res, err := s.client.ResolveAll(ctx, connect.NewRequest(&schemaV1.ResolveAllRequest{
+		Context: evalCtxF,
+	}))
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	details := make(map[string]of.InterfaceResolutionDetail, len(res.Msg.Flags))
+	for key, flag := range res.Msg.Flags {
+		resolution := of.ProviderResolutionDetail{
+			Reason:  of.Reason(flag.Reason),
+			Variant: flag.Variant,
+		}
+
+		var value interface{}
+		switch v := flag.Value.(type) {
+		case *schemaV1.AnyFlag_BoolValue:
+			value = v.BoolValue
+		case *schemaV1.AnyFlag_StringValue:
+			value = v.StringValue
+		case *schemaV1.AnyFlag_DoubleValue:
+			value = v.DoubleValue
+		case *schemaV1.AnyFlag_ObjectValue:
+			value = v.ObjectValue.AsMap()
+		default:
+			resolution.Reason = of.ErrorReason
+			resolution.ResolutionError = of.NewParseErrorResolutionError(
+				fmt.Sprintf("flag: %s resolved without a supported value type", key))
+		}
+
+		details[key] = of.InterfaceResolutionDetail{
+			Value:                    value,
+			ProviderResolutionDetail: resolution,
+		}
+	}
+
+	return details, nil
+}
+
+// getFromCache looks up a cached resolution of the flag. Static resolutions are keyed by the flag key only, while
+// targeted resolutions are keyed by the flag key and the evaluation context if context aware caching is enabled.
+func (s *Service) getFromCache(key string, evalCtx map[string]interface{}) (interface{}, bool) {
+	if !s.cache.IsEnabled() {
+		return nil, false
+	}
+
+	value, ok := s.lookupCache(key, evalCtx)
+	if ok {
+		s.observer().CacheHit(key)
+	} else {
+		s.observer().CacheMiss(key)
+	}
+
+	return value, ok
+}
+
+func (s *Service) lookupCache(key string, evalCtx map[string]interface{}) (interface{}, bool) {
+	fromCache, ok := s.cache.GetCache().Get(key)
+	if ok || !s.cfg.ContextAwareCache {
+		return fromCache, ok
+	}
+
+	ctxKey, err := contextCacheKey(key, evalCtx)
+	if err != nil {
+		s.logger.V(logger.Debug).Info(fmt.Sprintf("unable to derive cache key for flag %s: %s", key, err.Error()))
+		return nil, false
+	}
+
+	return s.cache.GetCache().Get(ctxKey)
+}
+
+// addToCache caches a resolution based on its reason. Static resolutions are always cached, while targeted
+// resolutions are only cached if context aware caching is enabled.
+func (s *Service) addToCache(key string, evalCtx map[string]interface{}, reason of.Reason, detail interface{}) {
+	if !s.cache.IsEnabled() {
+		return
+	}
+
+	switch reason {
+	case flagdModels.StaticReason:
+		if s.cache.GetCache().Add(key, detail) {
+			s.observer().CacheEviction()
+		}
+	case flagdModels.TargetingMatchReason, flagdModels.DefaultReason:
+		if !s.cfg.ContextAwareCache {
+			return
+		}
+
+		ctxKey, err := contextCacheKey(key, evalCtx)
+		if err != nil {
+			s.logger.V(logger.Debug).Info(fmt.Sprintf("unable to derive cache key for flag %s: %s", key, err.Error()))
+			return
+		}
+
+		if s.cache.GetCache().Add(ctxKey, detail) {
+			s.observer().CacheEviction()
+		}
+	}
+}
+
+// removeFromCache removes all cached resolutions of the given flags, including context aware entries
+func (s *Service) removeFromCache(flagKeys map[string]interface{}) {
+	for flagKey := range flagKeys {
+		s.cache.GetCache().Remove(flagKey)
+	}
+
+	if !s.cfg.ContextAwareCache {
+		return
+	}
+
+	for _, cacheKey := range s.cache.GetCache().Keys() {
+		idx := strings.LastIndex(cacheKey, cacheKeySeparator)
+		if idx < 0 {
+			continue
+		}
+
+		if _, ok := flagKeys[cacheKey[:idx]]; ok {
+			s.cache.GetCache().Remove(cacheKey)
+		}
+	}
+}
+
+// contextCacheKey derives a cache key from the flag key and the fingerprint of the flattened evaluation context
+func contextCacheKey(flagKey string, evalCtx map[string]interface{}) (string, error) {
+	fingerprint, err := ContextFingerprint(evalCtx)
+	if err != nil {
+		return "", err
+	}
+
+	return flagKey + cacheKeySeparator + fingerprint, nil
+}
+
+// ContextFingerprint is a stable hash of the flattened evaluation context. Map keys are sorted by the json encoder,
+// hence equal contexts always result in the same fingerprint.
+func ContextFingerprint(evalCtx map[string]interface{}) (string, error) {
+	ctxBytes, err := json.Marshal(evalCtx)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(ctxBytes)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 func (s *Service) isInitialised() bool {
 	return s.client != nil
 }
@@ -436,37 +651,70 @@ func (s *Service) EventChannel() <-chan of.Event {
 
 // startEventStream - starts listening to flagd event stream with retries.
 // This contains blocking calls and busy wait backed retry attempts, hence must be called concurrently.
-// If retrying is exhausted, an event with openfeature.ProviderError will be emitted.
+// Once an established stream is lost, an event with openfeature.ProviderStale is emitted and cached values are served
+// for the grace period. If the stream is not re-established within the grace period, the cache is disabled and an
+// event with openfeature.ProviderError is emitted. If retrying is exhausted before a stream was ever established, the
+// cache is disabled and the error event is emitted right away.
+// With unlimited retries, connection attempts continue and a successful connection re-enables the cache.
 func (s *Service) startEventStream(ctx context.Context) {
+	exhausted := false
+
 	// wraps connection with retry attempts
-	for s.retryCounter.retry() {
-		s.logger.V(logger.Debug).Info("connecting to event stream")
-		err := s.streamClient(ctx)
-		if err != nil {
-			// first check for ctx close and exit retrying as this is a shutdown
-			if errors.Is(ctx.Err(), context.Canceled) {
-				s.logger.V(logger.Debug).Info("context cancelled, exiting")
+	for {
+		if s.retryCounter.Retry() {
+			exhausted = false
+		} else {
+			if !exhausted {
+				exhausted = true
+
+				// retry attempts exhausted. Unless the grace period reports the lost connection, disable cache and
+				// emit error event
+				if !s.gracePeriod.IsLost() {
+					s.disableCache()
+					s.events <- of.Event{
+						ProviderName: "flagd",
+						EventType:    of.ProviderError,
+						ProviderEventDetails: of.ProviderEventDetails{
+							Message: "grpc connection establishment failed",
+						},
+					}
+				}
+			}
+
+			if !s.retryCounter.Unlimited() {
 				return
 			}
+		}
+
+		s.logger.V(logger.Debug).Info("connecting to event stream")
+		err := s.streamClient(ctx)
+
+		// first check for ctx close and exit retrying as this is a shutdown
+		if errors.Is(ctx.Err(), context.Canceled) {
+			s.logger.V(logger.Debug).Info("context cancelled, exiting")
+			return
+		}
 
-			// error in stream handler, purge cache if available and retry
+		if err != nil {
 			s.logger.V(logger.Warn).Info("connection to event stream failed, attempting again")
-			if s.cache.IsEnabled() {
-				s.cache.GetCache().Purge()
-			}
 		}
 
-		time.Sleep(s.retryCounter.sleep())
-	}
+		// stream ended, serve cached values for the grace period if the stream was established
+		if s.connected {
+			s.connected = false
+			s.observer().Disconnected(s.source())
+			s.gracePeriod.Lost()
+		}
 
-	// retry attempts exhausted. Disable cache and emit error event
-	s.cache.Disable()
-	s.events <- of.Event{
-		ProviderName: "flagd",
-		EventType:    of.ProviderError,
-		ProviderEventDetails: of.ProviderEventDetails{
-			Message: "grpc connection establishment failed",
-		},
+		delay := s.retryCounter.Sleep()
+		s.observer().RetryAttempt(s.source(), delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			s.logger.V(logger.Debug).Info("context cancelled, exiting")
+			return
+		}
 	}
 }
 
@@ -481,7 +729,7 @@ func (s *Service) streamClient(ctx context.Context) error {
 
 	for stream.Receive() {
 		// reset retry counters and proceed to message handling
-		s.retryCounter.reset()
+		s.retryCounter.Reset()
 
 		switch stream.Msg().Type {
 		case string(flagdService.ConfigurationChange):
@@ -505,51 +753,140 @@ func (s *Service) streamClient(ctx context.Context) error {
 }
 
 func (s *Service) handleConfigurationChangeEvent(event *schemaV1.EventStreamResponse) {
-	if !s.cache.IsEnabled() {
-		return
+	// resolutions of a prior run are only trusted until the configuration changes
+	s.invalidatePersistedCache()
+
+	var flags map[string]interface{}
+	if event.Data != nil {
+		flags, _ = event.Data.AsMap()["flags"].(map[string]interface{})
 	}
 
-	if event.Data == nil {
-		// purge cache and return
-		s.cache.GetCache().Purge()
-		return
+	keys := make([]string, 0, len(flags))
+	changeMetadata := make(map[string]interface{}, len(flags))
+
+	for flagKey, flag := range flags {
+		keys = append(keys, flagKey)
+
+		var changeType, newDefaultVariant string
+		if notification, ok := flag.(map[string]interface{}); ok {
+			changeType, _ = notification["type"].(string)
+			newDefaultVariant, _ = notification["defaultVariant"].(string)
+		}
+		changeMetadata[flagKey] = flagchanges.Change(
+			flagchanges.TypeOf(changeType), s.cachedDefaultVariant(flagKey), newDefaultVariant)
 	}
+	sort.Strings(keys)
 
-	flagsVal, ok := event.Data.AsMap()["flags"]
-	if !ok {
-		// purge cache and return
-		s.cache.GetCache().Purge()
-		return
+	if s.cache.IsEnabled() {
+		if flags == nil {
+			// changed flags are unknown, hence all cached resolutions are purged
+			s.purgeCache()
+		} else {
+			s.removeFromCache(flags)
+		}
 	}
+	s.observer().SyncUpdate(s.source(), len(keys))
 
-	flags, ok := flagsVal.(map[string]interface{})
+	s.events <- of.Event{
+		ProviderName: "flagd",
+		EventType:    of.ProviderConfigChange,
+		ProviderEventDetails: of.ProviderEventDetails{
+			Message:       "flags changed",
+			FlagChanges:   keys,
+			EventMetadata: map[string]interface{}{flagchanges.MetadataKey: changeMetadata},
+		},
+	}
+}
+
+// cachedDefaultVariant returns the variant of the cached static resolution of the flag, which is its default variant
+// as static resolutions are not targeted. Empty if no static resolution is cached
+func (s *Service) cachedDefaultVariant(flagKey string) string {
+	if !s.cache.IsEnabled() {
+		return ""
+	}
+
+	cached, ok := s.cache.GetCache().Get(flagKey)
 	if !ok {
-		// purge cache and return
-		s.cache.GetCache().Purge()
-		return
+		return ""
 	}
 
-	keys := make([]string, len(flags))
+	return providerDetailOf(cached).Variant
+}
 
-	for flagKey := range flags {
-		s.cache.GetCache().Remove(flagKey)
-		keys = append(keys, flagKey)
+func (s *Service) handleReadyEvent() {
+	// connection is re-established, cached values may have changed while the stream was lost
+	if s.gracePeriod.Restored() && s.cache.IsEnabled() {
+		s.purgeCache()
 	}
 
+	// re-enable the cache if it was disabled due to exhausted retries or an expired grace period
+	s.cache.Enable()
+	s.observer().CacheStatus(s.cache.IsEnabled())
+	s.connected = true
+	s.observer().Connected(s.source())
+
 	s.events <- of.Event{
 		ProviderName: "flagd",
-		EventType:    of.ProviderConfigChange,
+		EventType:    of.ProviderReady,
+	}
+}
+
+// purgeCache removes all cached resolutions
+func (s *Service) purgeCache() {
+	s.cache.GetCache().Purge()
+	s.observer().CachePurge()
+}
+
+// disableCache disables the cache, which purges cached resolutions
+func (s *Service) disableCache() {
+	wasEnabled := s.cache.IsEnabled()
+	s.cache.Disable()
+
+	if wasEnabled {
+		s.observer().CachePurge()
+	}
+	s.observer().CacheStatus(false)
+}
+
+// observer returns the configured observer, or a no-op observer
+func (s *Service) observer() observer.Observer {
+	return observer.OrNoop(s.cfg.Observer)
+}
+
+// source returns the address of flagd, reported to the observer
+func (s *Service) source() string {
+	if s.cfg.SocketPath != "" {
+		return s.cfg.SocketPath
+	}
+
+	return fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.Port)
+}
+
+// handleStale emits an event with openfeature.ProviderStale once an established event stream is lost
+func (s *Service) handleStale() {
+	s.logger.V(logger.Warn).Info(
+		fmt.Sprintf("event stream lost, serving cached values for %s", s.cfg.RetryGracePeriod))
+
+	s.events <- of.Event{
+		ProviderName: "flagd",
+		EventType:    of.ProviderStale,
 		ProviderEventDetails: of.ProviderEventDetails{
-			Message:     "flags changed",
-			FlagChanges: keys,
+			Message: "connection to event stream lost",
 		},
 	}
 }
 
-func (s *Service) handleReadyEvent() {
+// handleGracePeriodExpiry disables the cache and emits an event with openfeature.ProviderError once the event stream
+// was not re-established within the grace period
+func (s *Service) handleGracePeriodExpiry() {
+	s.disableCache()
+
 	s.events <- of.Event{
 		ProviderName: "flagd",
-		EventType:    of.ProviderReady,
+		EventType:    of.ProviderError,
+		ProviderEventDetails: of.ProviderEventDetails{
+			Message: fmt.Sprintf("connection to event stream not re-established within %s", s.cfg.RetryGracePeriod),
+		},
 	}
 }
 
@@ -567,23 +904,43 @@ func newClient(cfg Configuration) (schemaConnectV1.ServiceClient, error) {
 	// tls
 	if cfg.TLSEnabled {
 		url = fmt.Sprintf("https://%s:%d", cfg.Host, cfg.Port)
-		tlsConfig = &tls.Config{}
-		if cfg.CertificatePath != "" {
-			caCert, err := os.ReadFile(cfg.CertificatePath)
-			if err != nil {
-				return nil, err
-			}
-			caCertPool := x509.NewCertPool()
-			if !caCertPool.AppendCertsFromPEM(caCert) {
-				return nil, errors.New("error appending provider certificate file. please check and try again")
-			}
-			tlsConfig.RootCAs = caCertPool
+		var err error
+		tlsConfig, err = tlsconfig.New(tlsconfig.Options{
+			CertificatePath: cfg.CertificatePath,
+			ClientCertPath:  cfg.ClientCertPath,
+			ClientKeyPath:   cfg.ClientKeyPath,
+			ServerName:      cfg.ServerName,
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
 
 	// build options
 	var options []connect.ClientOption
 
+	switch cfg.Protocol {
+	case "", ProtocolConnect:
+	case ProtocolGRPC:
+		options = append(options, connect.WithGRPC())
+	case ProtocolGRPCWeb:
+		options = append(options, connect.WithGRPCWeb())
+	default:
+		return nil, fmt.Errorf("unsupported protocol %q", cfg.Protocol)
+	}
+
+	switch cfg.Compression {
+	case "":
+	case CompressionGzip:
+		options = append(options, connect.WithSendGzip())
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", cfg.Compression)
+	}
+
+	if cfg.CallTimeout > 0 {
+		options = append(options, connect.WithInterceptors(callTimeout(cfg.CallTimeout)))
+	}
+
 	if cfg.OtelInterceptor {
 		interceptor, err := otelconnect.NewInterceptor()
 		if err != nil {
@@ -593,14 +950,54 @@ func newClient(cfg Configuration) (schemaConnectV1.ServiceClient, error) {
 		options = append(options, connect.WithInterceptors(interceptor))
 	}
 
+	if injector := headers.New(cfg.Headers, cfg.TokenSource); injector != nil {
+		options = append(options, connect.WithInterceptors(injector.Interceptor()))
+	}
+
+	var transport http.RoundTripper = &http.Transport{
+		TLSClientConfig:   tlsConfig,
+		DialContext:       dialContext,
+		ForceAttemptHTTP2: true,
+	}
+
+	// HTTP/2 is negotiated with TLS, hence cleartext connections require the HTTP/2 transport
+	if cfg.H2C {
+		if cfg.TLSEnabled {
+			return nil, errors.New("h2c requires a connection without TLS")
+		}
+
+		if dialContext == nil {
+			dialContext = (&net.Dialer{}).DialContext
+		}
+
+		transport = &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network string, addr string, _ *tls.Config) (net.Conn, error) {
+				return dialContext(ctx, network, addr)
+			},
+		}
+	}
+
 	return schemaConnectV1.NewServiceClient(
 		&http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: tlsConfig,
-				DialContext:     dialContext,
-			},
+			Transport: transport,
 		},
 		url,
 		options...,
 	), nil
 }
+
+// callTimeout applies the timeout to unary calls without a deadline of their context. Streams are not bounded
+func callTimeout(timeout time.Duration) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, request connect.AnyRequest) (connect.AnyResponse, error) {
+			if _, ok := ctx.Deadline(); !ok {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			return next(ctx, request)
+		}
+	}
+}
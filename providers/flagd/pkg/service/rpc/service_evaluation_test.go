@@ -4,7 +4,9 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	schemaConnectV1 "buf.build/gen/go/open-feature/flagd/connectrpc/go/flagd/evaluation/v1/evaluationv1connect"
 	v1 "buf.build/gen/go/open-feature/flagd/protocolbuffers/go/flagd/evaluation/v1"
@@ -12,6 +14,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/cache"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/observer"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/retry"
 	of "github.com/open-feature/go-sdk/openfeature"
 	"google.golang.org/protobuf/types/known/structpb"
 )
@@ -56,7 +60,7 @@ func TestBooleanEvaluation(t *testing.T) {
 			name: "happy path - simple uncached evaluation",
 			getCache: func() *cache.Service {
 				// disable cache
-				return cache.NewCacheService(cache.DisabledValue, 10, log)
+				return cache.NewCacheService(cache.DisabledValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -81,7 +85,7 @@ func TestBooleanEvaluation(t *testing.T) {
 		{
 			name: "cached flags are served with cache reason",
 			getCache: func() *cache.Service {
-				cacheService := cache.NewCacheService(cache.InMemValue, 10, log)
+				cacheService := cache.NewCacheService(cache.InMemValue, 10, 0, log)
 
 				cacheService.GetCache().Add(flagKey, of.BoolResolutionDetail{
 					Value: true,
@@ -111,7 +115,7 @@ func TestBooleanEvaluation(t *testing.T) {
 		{
 			name: "static resolving will be cached",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.InMemValue, 10, log)
+				return cache.NewCacheService(cache.InMemValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -136,7 +140,7 @@ func TestBooleanEvaluation(t *testing.T) {
 		{
 			name: "simple error check - flag not found",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -155,7 +159,7 @@ func TestBooleanEvaluation(t *testing.T) {
 		{
 			name: "simple error check - client not initialised",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return nil
@@ -191,7 +195,7 @@ func TestStringEvaluation(t *testing.T) {
 			name: "happy path - simple uncached evaluation",
 			getCache: func() *cache.Service {
 				// disable cache
-				return cache.NewCacheService(cache.DisabledValue, 10, log)
+				return cache.NewCacheService(cache.DisabledValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -216,7 +220,7 @@ func TestStringEvaluation(t *testing.T) {
 		{
 			name: "cached flags are served with cache reason",
 			getCache: func() *cache.Service {
-				cacheService := cache.NewCacheService(cache.InMemValue, 10, log)
+				cacheService := cache.NewCacheService(cache.InMemValue, 10, 0, log)
 
 				cacheService.GetCache().Add(flagKey, of.StringResolutionDetail{
 					Value: "valid",
@@ -246,7 +250,7 @@ func TestStringEvaluation(t *testing.T) {
 		{
 			name: "static resolving will be cached",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.InMemValue, 10, log)
+				return cache.NewCacheService(cache.InMemValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -271,7 +275,7 @@ func TestStringEvaluation(t *testing.T) {
 		{
 			name: "simple error check - flag not found",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -290,7 +294,7 @@ func TestStringEvaluation(t *testing.T) {
 		{
 			name: "simple error check - client not initialised",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return nil
@@ -326,7 +330,7 @@ func TestFloatEvaluation(t *testing.T) {
 			name: "happy path - simple uncached evaluation",
 			getCache: func() *cache.Service {
 				// disable cache
-				return cache.NewCacheService(cache.DisabledValue, 10, log)
+				return cache.NewCacheService(cache.DisabledValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -351,7 +355,7 @@ func TestFloatEvaluation(t *testing.T) {
 		{
 			name: "cached flags are served with cache reason",
 			getCache: func() *cache.Service {
-				cacheService := cache.NewCacheService(cache.InMemValue, 10, log)
+				cacheService := cache.NewCacheService(cache.InMemValue, 10, 0, log)
 
 				cacheService.GetCache().Add(flagKey, of.FloatResolutionDetail{
 					Value: 1.005,
@@ -381,7 +385,7 @@ func TestFloatEvaluation(t *testing.T) {
 		{
 			name: "static resolving will be cached",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.InMemValue, 10, log)
+				return cache.NewCacheService(cache.InMemValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -406,7 +410,7 @@ func TestFloatEvaluation(t *testing.T) {
 		{
 			name: "simple error check - flag not found",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -425,7 +429,7 @@ func TestFloatEvaluation(t *testing.T) {
 		{
 			name: "simple error check - client not initialised",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return nil
@@ -461,7 +465,7 @@ func TestIntEvaluation(t *testing.T) {
 			name: "happy path - simple uncached evaluation",
 			getCache: func() *cache.Service {
 				// disable cache
-				return cache.NewCacheService(cache.DisabledValue, 10, log)
+				return cache.NewCacheService(cache.DisabledValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -486,7 +490,7 @@ func TestIntEvaluation(t *testing.T) {
 		{
 			name: "cached flags are served with cache reason",
 			getCache: func() *cache.Service {
-				cacheService := cache.NewCacheService(cache.InMemValue, 10, log)
+				cacheService := cache.NewCacheService(cache.InMemValue, 10, 0, log)
 
 				cacheService.GetCache().Add(flagKey, of.IntResolutionDetail{
 					Value: 2,
@@ -516,7 +520,7 @@ func TestIntEvaluation(t *testing.T) {
 		{
 			name: "static resolving will be cached",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.InMemValue, 10, log)
+				return cache.NewCacheService(cache.InMemValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -541,7 +545,7 @@ func TestIntEvaluation(t *testing.T) {
 		{
 			name: "simple error check - flag not found",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -560,7 +564,7 @@ func TestIntEvaluation(t *testing.T) {
 		{
 			name: "simple error check - client not initialised",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return nil
@@ -609,7 +613,7 @@ func TestObjectEvaluation(t *testing.T) {
 			name: "happy path - simple uncached evaluation",
 			getCache: func() *cache.Service {
 				// disable cache
-				return cache.NewCacheService(cache.DisabledValue, 10, log)
+				return cache.NewCacheService(cache.DisabledValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -634,7 +638,7 @@ func TestObjectEvaluation(t *testing.T) {
 		{
 			name: "cached flags are served with cache reason",
 			getCache: func() *cache.Service {
-				cacheService := cache.NewCacheService(cache.InMemValue, 10, log)
+				cacheService := cache.NewCacheService(cache.InMemValue, 10, 0, log)
 
 				cacheService.GetCache().Add(flagKey, of.InterfaceResolutionDetail{
 					Value: expectedValue,
@@ -664,7 +668,7 @@ func TestObjectEvaluation(t *testing.T) {
 		{
 			name: "static resolving will be cached",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.InMemValue, 10, log)
+				return cache.NewCacheService(cache.InMemValue, 10, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -689,7 +693,7 @@ func TestObjectEvaluation(t *testing.T) {
 		{
 			name: "simple error check - flag not found",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return &MockClient{
@@ -708,7 +712,7 @@ func TestObjectEvaluation(t *testing.T) {
 		{
 			name: "simple error check - client not ready",
 			getCache: func() *cache.Service {
-				return cache.NewCacheService(cache.DisabledValue, 0, log)
+				return cache.NewCacheService(cache.DisabledValue, 0, 0, log)
 			},
 			getMockClient: func() schemaConnectV1.ServiceClient {
 				return nil
@@ -794,3 +798,285 @@ func TestService_isInitialised(t *testing.T) {
 		})
 	}
 }
+
+func TestContextAwareCaching(t *testing.T) {
+	evalCtx := map[string]interface{}{
+		"targetingKey": "user-1",
+		"email":        "user@example.com",
+	}
+
+	client := &MockClient{
+		booleanResponse: v1.ResolveBooleanResponse{
+			Value:    true,
+			Reason:   string(of.TargetingMatchReason),
+			Variant:  "on",
+			Metadata: metadataStruct,
+		},
+	}
+
+	t.Run("targeted evaluations are not cached by default", func(t *testing.T) {
+		service := Service{
+			cache:  cache.NewCacheService(cache.InMemValue, 10, 0, log),
+			logger: log,
+			client: client,
+		}
+
+		service.ResolveBoolean(context.Background(), flagKey, false, evalCtx)
+
+		if keys := service.cache.GetCache().Keys(); len(keys) != 0 {
+			t.Errorf("expected no cached entries, but got %v", keys)
+		}
+	})
+
+	t.Run("targeted evaluations are cached by evaluation context", func(t *testing.T) {
+		service := Service{
+			cache:  cache.NewCacheService(cache.InMemValue, 10, 0, log),
+			cfg:    Configuration{ContextAwareCache: true},
+			logger: log,
+			client: client,
+		}
+
+		detail := service.ResolveBoolean(context.Background(), flagKey, false, evalCtx)
+		if detail.Reason != of.TargetingMatchReason {
+			t.Fatalf("expected reason %s, got %s", of.TargetingMatchReason, detail.Reason)
+		}
+
+		detail = service.ResolveBoolean(context.Background(), flagKey, false, map[string]interface{}{
+			"email":        "user@example.com",
+			"targetingKey": "user-1",
+		})
+		if detail.Reason != of.CachedReason {
+			t.Errorf("expected reason %s for an equal context, got %s", of.CachedReason, detail.Reason)
+		}
+
+		detail = service.ResolveBoolean(context.Background(), flagKey, false, map[string]interface{}{
+			"targetingKey": "user-2",
+		})
+		if detail.Reason != of.TargetingMatchReason {
+			t.Errorf("expected reason %s for a different context, got %s", of.TargetingMatchReason, detail.Reason)
+		}
+	})
+}
+
+func TestContextCacheKey(t *testing.T) {
+	first, err := contextCacheKey(flagKey, map[string]interface{}{
+		"targetingKey": "user-1",
+		"nested":       map[string]interface{}{"a": 1, "b": "2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := contextCacheKey(flagKey, map[string]interface{}{
+		"nested":       map[string]interface{}{"b": "2", "a": 1},
+		"targetingKey": "user-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first != second {
+		t.Errorf("expected equal contexts to result in the same key, got %s and %s", first, second)
+	}
+
+	if !strings.HasPrefix(first, flagKey+cacheKeySeparator) {
+		t.Errorf("expected key %s to be prefixed with the flag key", first)
+	}
+}
+
+func TestResolveAll(t *testing.T) {
+	objectValue, err := structpb.NewStruct(map[string]interface{}{"key": "value"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &MockClient{
+		allResponse: v1.ResolveAllResponse{
+			Flags: map[string]*v1.AnyFlag{
+				"bool": {
+					Reason:  string(of.StaticReason),
+					Variant: "on",
+					Value:   &v1.AnyFlag_BoolValue{BoolValue: true},
+				},
+				"string": {
+					Reason:  string(of.TargetingMatchReason),
+					Variant: "greeting",
+					Value:   &v1.AnyFlag_StringValue{StringValue: "hi"},
+				},
+				"float": {
+					Reason:  string(of.StaticReason),
+					Variant: "pi",
+					Value:   &v1.AnyFlag_DoubleValue{DoubleValue: 3.14},
+				},
+				"object": {
+					Reason:  string(of.StaticReason),
+					Variant: "obj",
+					Value:   &v1.AnyFlag_ObjectValue{ObjectValue: objectValue},
+				},
+			},
+		},
+	}
+
+	service := Service{
+		cache:  cache.NewCacheService(cache.InMemValue, 10, 0, log),
+		logger: log,
+		client: client,
+	}
+
+	details, err := service.ResolveAll(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]of.InterfaceResolutionDetail{
+		"bool": {
+			Value:                    true,
+			ProviderResolutionDetail: of.ProviderResolutionDetail{Reason: of.StaticReason, Variant: "on"},
+		},
+		"string": {
+			Value:                    "hi",
+			ProviderResolutionDetail: of.ProviderResolutionDetail{Reason: of.TargetingMatchReason, Variant: "greeting"},
+		},
+		"float": {
+			Value:                    3.14,
+			ProviderResolutionDetail: of.ProviderResolutionDetail{Reason: of.StaticReason, Variant: "pi"},
+		},
+		"object": {
+			Value:                    map[string]interface{}{"key": "value"},
+			ProviderResolutionDetail: of.ProviderResolutionDetail{Reason: of.StaticReason, Variant: "obj"},
+		},
+	}
+
+	if diff := cmp.Diff(
+		expected, details,
+		cmpopts.IgnoreFields(of.ProviderResolutionDetail{}, "ResolutionError"),
+	); diff != "" {
+		t.Errorf("mismatch (-expected +got):\n%s", diff)
+	}
+
+	// bulk resolutions lack flag metadata, hence they do not populate the cache of single flag resolutions
+	if keys := service.cache.GetCache().Keys(); len(keys) != 0 {
+		t.Errorf("expected bulk resolutions not to be cached, got %v", keys)
+	}
+}
+
+func TestResolveAllNotInitialised(t *testing.T) {
+	service := Service{
+		cache:  cache.NewCacheService(cache.DisabledValue, 0, 0, log),
+		logger: log,
+	}
+
+	_, err := service.ResolveAll(context.Background(), map[string]interface{}{})
+	if err == nil {
+		t.Fatal("expected an error for an uninitialised client")
+	}
+}
+
+// recordingObserver counts cache notifications
+type recordingObserver struct {
+	observer.Noop
+	mtx                     sync.Mutex
+	hits, misses, evictions int
+	purges, expiries        int
+	cacheEnabled            bool
+}
+
+func (r *recordingObserver) CacheHit(string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.hits++
+}
+
+func (r *recordingObserver) CacheMiss(string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.misses++
+}
+
+func (r *recordingObserver) CacheEviction() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.evictions++
+}
+
+func (r *recordingObserver) CacheExpiry() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.expiries++
+}
+
+func (r *recordingObserver) CachePurge() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.purges++
+}
+
+func (r *recordingObserver) CacheStatus(enabled bool) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.cacheEnabled = enabled
+}
+
+func TestCacheObserver(t *testing.T) {
+	// given - a cache holding a single entry
+	recorder := &recordingObserver{cacheEnabled: true}
+	service := Service{
+		cache:  cache.NewCacheService(cache.LRUValue, 1, 0, log),
+		cfg:    Configuration{Observer: recorder},
+		logger: log,
+		client: &MockClient{
+			booleanResponse: v1.ResolveBooleanResponse{
+				Value:    true,
+				Reason:   string(of.StaticReason),
+				Variant:  "on",
+				Metadata: metadataStruct,
+			},
+		},
+	}
+
+	// when
+	service.ResolveBoolean(context.Background(), "a", false, nil)
+	service.ResolveBoolean(context.Background(), "a", false, nil)
+	service.ResolveBoolean(context.Background(), "b", false, nil)
+	service.disableCache()
+
+	// then
+	if recorder.hits != 1 || recorder.misses != 2 || recorder.evictions != 1 {
+		t.Errorf("expected 1 hit, 2 misses and 1 eviction, got %d hits, %d misses and %d evictions",
+			recorder.hits, recorder.misses, recorder.evictions)
+	}
+
+	if recorder.purges != 1 || recorder.cacheEnabled {
+		t.Errorf("expected the disabled cache to be purged, got %d purges and enabled %v",
+			recorder.purges, recorder.cacheEnabled)
+	}
+}
+
+func TestCacheObserverExpiry(t *testing.T) {
+	// given - a cache with a short time-to-live
+	recorder := &recordingObserver{}
+	service := NewService(Configuration{Observer: recorder},
+		cache.NewCacheService(cache.TTLValue, 10, 50*time.Millisecond, log), log, retry.Policy{})
+	defer service.cache.Close()
+
+	service.client = &MockClient{
+		booleanResponse: v1.ResolveBooleanResponse{
+			Value:   true,
+			Reason:  string(of.StaticReason),
+			Variant: "on",
+		},
+	}
+
+	// when
+	service.ResolveBoolean(context.Background(), "a", false, nil)
+	time.Sleep(100 * time.Millisecond)
+	service.ResolveBoolean(context.Background(), "a", false, nil)
+
+	// then - the expired entry is reported
+	recorder.mtx.Lock()
+	defer recorder.mtx.Unlock()
+
+	if recorder.expiries != 1 {
+		t.Errorf("expected 1 expiry, got %d", recorder.expiries)
+	}
+}
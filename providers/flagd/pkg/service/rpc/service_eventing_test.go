@@ -5,8 +5,11 @@ import (
 	"context"
 	"errors"
 	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/cache"
+	"github.com/open-feature/go-sdk-contrib/providers/flagd/internal/retry"
 	of "github.com/open-feature/go-sdk/openfeature"
 	"google.golang.org/protobuf/types/known/structpb"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -18,13 +21,14 @@ func TestRetries(t *testing.T) {
 	}
 
 	service := Service{
-		retryCounter: retryCounter{
-			baseRetryDelay: 100 * time.Millisecond,
-			maxRetries:     1,
-		},
-		client: &client,
-		cache:  cache.NewCacheService(cache.DisabledValue, 0, log),
-		events: make(chan of.Event),
+		retryCounter: retry.NewCounter(retry.Policy{
+			BaseDelay:   100 * time.Millisecond,
+			MaxAttempts: 1,
+		}),
+		gracePeriod: retry.NewGracePeriod(0, func() {}, func() {}),
+		client:      &client,
+		cache:       cache.NewCacheService(cache.DisabledValue, 0, 0, log),
+		events:      make(chan of.Event),
 	}
 
 	// when - start event stream, knowing it will result in error
@@ -47,6 +51,62 @@ func TestRetries(t *testing.T) {
 	}
 }
 
+func TestStaleGracePeriod(t *testing.T) {
+	// given - an established stream which is lost and never re-established
+	client := MockClient{
+		error: errors.New("streaming error"),
+	}
+
+	service := NewService(Configuration{RetryGracePeriod: 200 * time.Millisecond},
+		cache.NewCacheService(cache.InMemValue, 10, 0, log), log,
+		retry.Policy{BaseDelay: 50 * time.Millisecond, MaxAttempts: 1})
+	service.client = &client
+	service.connected = true
+	service.addToCache("flag", nil, of.StaticReason, of.BoolResolutionDetail{Value: true})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// when
+	go func() {
+		service.startEventStream(ctx)
+	}()
+
+	// then - expect a stale event while cached values are still served
+	select {
+	case event := <-service.EventChannel():
+		if event.EventType != of.ProviderStale {
+			t.Fatalf("expected event %s, got %s", of.ProviderStale, event.EventType)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for stale event")
+	}
+
+	if _, ok := service.getFromCache("flag", nil); !ok {
+		t.Error("expected cached value to be served during the grace period")
+	}
+
+	// then - expect a single error event once the grace period expired
+	select {
+	case event := <-service.EventChannel():
+		if event.EventType != of.ProviderError {
+			t.Fatalf("expected event %s, got %s", of.ProviderError, event.EventType)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for error event")
+	}
+
+	if service.cache.IsEnabled() {
+		t.Error("expected cache to be disabled once the grace period expired")
+	}
+
+	select {
+	case event := <-service.EventChannel():
+		t.Fatalf("expected no further event, but got with type: %s", event.EventType)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
 func TestConfigChange(t *testing.T) {
 	data := map[string]interface{}{
 		"flags": map[string]interface{}{
@@ -60,10 +120,10 @@ func TestConfigChange(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	t.Run("no cache - do nothing", func(t *testing.T) {
+	t.Run("no cache - validate config change event", func(t *testing.T) {
 		// given
 		service := Service{
-			cache:  cache.NewCacheService(cache.DisabledValue, 0, log),
+			cache:  cache.NewCacheService(cache.DisabledValue, 0, 0, log),
 			events: make(chan of.Event),
 		}
 
@@ -74,20 +134,24 @@ func TestConfigChange(t *testing.T) {
 			})
 		}()
 
-		// then - expect no event
+		// then - expect config change event
 		select {
 		case event := <-service.EventChannel():
-			t.Fatalf("expected no event, but got with type: %s", event.EventType)
+			if event.EventType != of.ProviderConfigChange {
+				t.Fatalf("expected event %s, got %s", of.ProviderConfigChange, event.EventType)
+			}
+			if !reflect.DeepEqual(event.FlagChanges, []string{"a", "b"}) {
+				t.Fatalf("expected flag changes [a b], got %v", event.FlagChanges)
+			}
 		case <-time.After(100 * time.Millisecond):
-			// no events mean pass
-			break
+			t.Fatalf("timed out waiting for event")
 		}
 	})
 
 	t.Run("with cache - validate config change event", func(t *testing.T) {
 		// given
 		service := Service{
-			cache:  cache.NewCacheService(cache.InMemValue, 1, log),
+			cache:  cache.NewCacheService(cache.InMemValue, 1, 0, log),
 			events: make(chan of.Event),
 		}
 
@@ -98,7 +162,7 @@ func TestConfigChange(t *testing.T) {
 			})
 		}()
 
-		// then - expect no event
+		// then - expect config change event
 		select {
 		case event := <-service.EventChannel():
 			if event.EventType != of.ProviderConfigChange {
@@ -109,4 +173,111 @@ func TestConfigChange(t *testing.T) {
 		}
 	})
 
+	t.Run("with cache - describe flag changes", func(t *testing.T) {
+		// given
+		service := Service{
+			cache:  cache.NewCacheService(cache.InMemValue, 1, 0, log),
+			events: make(chan of.Event, 1),
+		}
+
+		notifications, err := structpb.NewStruct(map[string]interface{}{
+			"flags": map[string]interface{}{
+				"b": map[string]interface{}{"type": "delete", "source": "file"},
+				"a": map[string]interface{}{"type": "write", "source": "file"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// when
+		service.handleConfigurationChangeEvent(&schemaV1.EventStreamResponse{
+			Data: notifications,
+		})
+
+		// then - changed flags are sorted and described by their change type
+		event := <-service.EventChannel()
+		if !reflect.DeepEqual(event.FlagChanges, []string{"a", "b"}) {
+			t.Fatalf("expected flag changes [a b], got %v", event.FlagChanges)
+		}
+
+		expected := map[string]interface{}{
+			"a": map[string]interface{}{"type": "added"},
+			"b": map[string]interface{}{"type": "removed"},
+		}
+		if !reflect.DeepEqual(event.EventMetadata["changes"], expected) {
+			t.Fatalf("expected change metadata %v, got %v", expected, event.EventMetadata["changes"])
+		}
+	})
+
+	t.Run("with cache - describe default variants of changed flags", func(t *testing.T) {
+		// given
+		service := Service{
+			cache:  cache.NewCacheService(cache.InMemValue, 10, 0, log),
+			events: make(chan of.Event, 1),
+		}
+
+		service.addToCache("a", nil, of.StaticReason, of.StringResolutionDetail{
+			ProviderResolutionDetail: of.ProviderResolutionDetail{Reason: of.StaticReason, Variant: "foo"},
+		})
+		service.addToCache("b", nil, of.StaticReason, of.BoolResolutionDetail{
+			ProviderResolutionDetail: of.ProviderResolutionDetail{Reason: of.StaticReason, Variant: "on"},
+		})
+
+		notifications, err := structpb.NewStruct(map[string]interface{}{
+			"flags": map[string]interface{}{
+				"a": map[string]interface{}{"type": "update", "source": "file", "defaultVariant": "bar"},
+				"b": map[string]interface{}{"type": "delete", "source": "file"},
+				"c": map[string]interface{}{"type": "write", "source": "file"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// when
+		service.handleConfigurationChangeEvent(&schemaV1.EventStreamResponse{
+			Data: notifications,
+		})
+
+		// then - previous default variants are taken from cached static resolutions, new ones from the notification
+		event := <-service.EventChannel()
+		expected := map[string]interface{}{
+			"a": map[string]interface{}{"type": "updated", "oldDefaultVariant": "foo", "newDefaultVariant": "bar"},
+			"b": map[string]interface{}{"type": "removed", "oldDefaultVariant": "on"},
+			"c": map[string]interface{}{"type": "added"},
+		}
+		if !reflect.DeepEqual(event.EventMetadata["changes"], expected) {
+			t.Fatalf("expected change metadata %v, got %v", expected, event.EventMetadata["changes"])
+		}
+
+		if keys := service.cache.GetCache().Keys(); len(keys) != 0 {
+			t.Fatalf("expected cached resolutions of changed flags to be removed, got %v", keys)
+		}
+	})
+
+	t.Run("with context aware cache - invalidate targeted entries of changed flags", func(t *testing.T) {
+		// given
+		service := Service{
+			cache:  cache.NewCacheService(cache.InMemValue, 10, 0, log),
+			cfg:    Configuration{ContextAwareCache: true},
+			events: make(chan of.Event, 1),
+		}
+
+		evalCtx := map[string]interface{}{"targetingKey": "user"}
+		service.addToCache("a", evalCtx, of.TargetingMatchReason, of.BoolResolutionDetail{})
+		service.addToCache("b", evalCtx, of.StaticReason, of.BoolResolutionDetail{})
+		service.addToCache("c", evalCtx, of.TargetingMatchReason, of.BoolResolutionDetail{})
+
+		// when
+		service.handleConfigurationChangeEvent(&schemaV1.EventStreamResponse{
+			Data: stData,
+		})
+
+		// then - only entries of the unchanged flag remain
+		keys := service.cache.GetCache().Keys()
+		if len(keys) != 1 || !strings.HasPrefix(keys[0], "c"+cacheKeySeparator) {
+			t.Fatalf("expected only the targeted entry of flag c to remain cached, got %v", keys)
+		}
+	})
 }
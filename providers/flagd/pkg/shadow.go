@@ -0,0 +1,225 @@
+package flagd
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+	process "github.com/open-feature/go-sdk-contrib/providers/flagd/pkg/service/in_process"
+	rpcService "github.com/open-feature/go-sdk-contrib/providers/flagd/pkg/service/rpc"
+	of "github.com/open-feature/go-sdk/openfeature"
+)
+
+// maxShadowEvaluations bounds the concurrent shadow evaluations. Sampled evaluations exceeding it are not shadowed
+const maxShadowEvaluations = 64
+
+// ShadowResult is the result of an evaluation compared by shadow evaluation
+type ShadowResult struct {
+	Value   interface{}
+	Variant string
+	Reason  of.Reason
+}
+
+// ShadowMismatch describes differing results of the serving and the shadow resolver
+type ShadowMismatch struct {
+	FlagKey string
+	// ContextFingerprint is a stable hash of the evaluation context, which identifies equal evaluation contexts
+	// without exposing their attributes
+	ContextFingerprint string
+	// Fields are the differing fields of the results: "value", "variant" or "reason"
+	Fields []string
+	// Serving is the result served to the caller
+	Serving ShadowResult
+	// Shadow is the result of the shadow resolver
+	Shadow ShadowResult
+}
+
+// ShadowMismatchHandler is called with mismatches of shadow evaluations. It is called concurrently, from goroutines
+// of shadow evaluations
+type ShadowMismatchHandler func(mismatch ShadowMismatch)
+
+// shadowConfiguration holds the options of WithShadowEvaluation
+type shadowConfiguration struct {
+	sampleRate float64
+	onMismatch ShadowMismatchHandler
+	options    []ProviderOption
+}
+
+// shadowEvaluation evaluates a sample of the evaluations of the provider with a shadow service, and reports
+// mismatching results
+type shadowEvaluation struct {
+	service    IService
+	sampleRate float64
+	onMismatch ShadowMismatchHandler
+	logger     logr.Logger
+
+	// ready is set while the shadow service is ready, as evaluations of a service which is not ready always mismatch
+	ready    atomic.Bool
+	inFlight chan struct{}
+	// done ends tracking the readiness of the shadow service, once it is shut down
+	done chan struct{}
+}
+
+// newShadowEvaluation creates the shadow service with the configuration of the provider, using the other resolver
+// and the options of the shadow configuration. Files and the observer of the provider are not shared with the shadow
+// service
+func newShadowEvaluation(cfg *providerConfiguration, log logr.Logger) *shadowEvaluation {
+	shadowCfg := *cfg
+	shadowCfg.Shadow = nil
+	shadowCfg.err = nil
+	shadowCfg.Observer = nil
+	shadowCfg.SnapshotPath = ""
+	shadowCfg.CachePersistencePath = ""
+
+	if cfg.CustomEvaluators != nil {
+		shadowCfg.CustomEvaluators = make(map[string]process.CustomEvaluator, len(cfg.CustomEvaluators))
+		for name, evaluator := range cfg.CustomEvaluators {
+			shadowCfg.CustomEvaluators[name] = evaluator
+		}
+	}
+
+	if cfg.Resolver == rpc {
+		shadowCfg.Resolver = inProcess
+	} else {
+		shadowCfg.Resolver = rpc
+	}
+
+	shadowProvider := &Provider{providerConfiguration: &shadowCfg, logger: log}
+	for _, opt := range cfg.Shadow.options {
+		opt(shadowProvider)
+	}
+
+	return &shadowEvaluation{
+		service:    newService(shadowProvider.providerConfiguration, shadowProvider.logger),
+		sampleRate: cfg.Shadow.sampleRate,
+		onMismatch: cfg.Shadow.onMismatch,
+		logger:     log,
+		inFlight:   make(chan struct{}, maxShadowEvaluations),
+	}
+}
+
+// init initializes the shadow service and tracks its readiness. Failures of the shadow service are logged, as they
+// must not affect the provider
+func (s *shadowEvaluation) init() {
+	if s == nil {
+		return
+	}
+
+	done := make(chan struct{})
+	s.done = done
+	events := s.service.EventChannel()
+
+	go func() {
+		for {
+			select {
+			case event := <-events:
+				switch event.EventType {
+				case of.ProviderReady, of.ProviderConfigChange:
+					s.ready.Store(true)
+				case of.ProviderError, of.ProviderStale:
+					s.ready.Store(false)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	if err := s.service.Init(); err != nil {
+		s.logger.Error(err, "failed to initialize shadow evaluation")
+	}
+}
+
+func (s *shadowEvaluation) shutdown() {
+	if s == nil {
+		return
+	}
+
+	if s.done != nil {
+		close(s.done)
+		s.done = nil
+	}
+
+	s.ready.Store(false)
+	s.service.Shutdown()
+}
+
+// evaluate evaluates a sample of the evaluations with the shadow service in the background, and compares its result
+// to the result served to the caller
+func (s *shadowEvaluation) evaluate(
+	ctx context.Context, flagKey string, evalCtx map[string]interface{},
+	value interface{}, detail of.ProviderResolutionDetail,
+	resolve func(ctx context.Context, service IService) (interface{}, of.ProviderResolutionDetail),
+) {
+	if s == nil || !s.ready.Load() || rand.Float64() >= s.sampleRate {
+		return
+	}
+
+	select {
+	case s.inFlight <- struct{}{}:
+	default:
+		return
+	}
+
+	// the shadow evaluation must not be canceled once the caller's evaluation returns
+	ctx = context.WithoutCancel(ctx)
+
+	go func() {
+		defer func() { <-s.inFlight }()
+
+		shadowValue, shadowDetail := resolve(ctx, s.service)
+		s.compare(flagKey, evalCtx,
+			ShadowResult{Value: value, Variant: detail.Variant, Reason: detail.Reason},
+			ShadowResult{Value: shadowValue, Variant: shadowDetail.Variant, Reason: shadowDetail.Reason})
+	}()
+}
+
+// compare reports differing results. Reasons are not compared if either result was served from the cache of the rpc
+// resolver, as cached results replace their reason
+func (s *shadowEvaluation) compare(flagKey string, evalCtx map[string]interface{}, serving, shadow ShadowResult) {
+	var fields []string
+	if !reflect.DeepEqual(serving.Value, shadow.Value) {
+		fields = append(fields, "value")
+	}
+
+	if serving.Variant != shadow.Variant {
+		fields = append(fields, "variant")
+	}
+
+	if serving.Reason != shadow.Reason && !isCachedReason(serving.Reason) && !isCachedReason(shadow.Reason) {
+		fields = append(fields, "reason")
+	}
+
+	if len(fields) == 0 {
+		return
+	}
+
+	fingerprint, err := rpcService.ContextFingerprint(evalCtx)
+	if err != nil {
+		fingerprint = fmt.Sprintf("unavailable: %s", err.Error())
+	}
+
+	mismatch := ShadowMismatch{
+		FlagKey:            flagKey,
+		ContextFingerprint: fingerprint,
+		Fields:             fields,
+		Serving:            serving,
+		Shadow:             shadow,
+	}
+
+	if s.onMismatch != nil {
+		s.onMismatch(mismatch)
+		return
+	}
+
+	s.logger.Info("shadow evaluation mismatch", "flagKey", mismatch.FlagKey,
+		"contextFingerprint", mismatch.ContextFingerprint, "fields", mismatch.Fields,
+		"serving", mismatch.Serving, "shadow", mismatch.Shadow)
+}
+
+func isCachedReason(reason of.Reason) bool {
+	return reason == rpcService.ReasonCached || reason == rpcService.ReasonPersisted
+}
@@ -11,8 +11,9 @@ import (
 )
 
 // InitializeTestSuite register provider supplier for this test run
-func InitializeTestSuite(providerSupplier func() openfeature.FeatureProvider) func(*godog.TestSuiteContext) {
+func InitializeTestSuite(providerSupplier func() openfeature.FeatureProvider, opts ...Option) func(*godog.TestSuiteContext) {
 	test_provider_supplier = providerSupplier
+	test_config = newConfig(opts...)
 	return func(suiteContext *godog.TestSuiteContext) {}
 }
 
@@ -0,0 +1,507 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// ctxHandlersKey is the key used to pass the event handlers of a scenario across context.Context
+type ctxHandlersKey struct{}
+
+// ctxFlagEvaluationKey is the key used to pass the last flag evaluation across context.Context
+type ctxFlagEvaluationKey struct{}
+
+// eventHandlers records the events received by the handlers added in a scenario
+type eventHandlers struct {
+	client *openfeature.Client
+
+	mtx       sync.Mutex
+	callbacks map[openfeature.EventType]openfeature.EventCallback
+	received  map[openfeature.EventType]chan openfeature.EventDetails
+	// last holds the details of the last event awaited per event type
+	last map[openfeature.EventType]openfeature.EventDetails
+}
+
+// flagEvaluation is an evaluation which can be repeated, to observe caching
+type flagEvaluation struct {
+	flagKey  string
+	evaluate func(ctx context.Context) (openfeature.InterfaceEvaluationDetails, error)
+
+	details openfeature.InterfaceEvaluationDetails
+	// checked is set once the details were checked by a step, hence the next step checking a value evaluates again
+	checked bool
+}
+
+// InitializeFlagdTestSuite register provider supplier and options for this test run
+func InitializeFlagdTestSuite(providerSupplier func() openfeature.FeatureProvider, opts ...Option) func(*godog.TestSuiteContext) {
+	test_provider_supplier = providerSupplier
+	test_config = newConfig(opts...)
+
+	return func(suiteContext *godog.TestSuiteContext) {}
+}
+
+// InitializeFlagdScenario initializes the flagd test scenario, covering events, caching, reconnection and zero values
+func InitializeFlagdScenario(ctx *godog.ScenarioContext) {
+	ctx.Step(`^a flagd provider is set$`, aFlagdProviderIsSet)
+	ctx.Step(`^a provider is registered$`, aFlagdProviderIsSet)
+
+	// events
+	ctx.Step(`^a (PROVIDER_READY|PROVIDER_ERROR|PROVIDER_STALE|PROVIDER_CONFIGURATION_CHANGED) handler is added$`, aHandlerIsAdded)
+	ctx.Step(`^a PROVIDER_READY handler and a PROVIDER_ERROR handler are added$`, aReadyHandlerAndAnErrorHandlerAreAdded)
+	ctx.Step(`^the (PROVIDER_READY|PROVIDER_ERROR|PROVIDER_STALE|PROVIDER_CONFIGURATION_CHANGED) handler must run$`, theHandlerMustRun)
+	ctx.Step(`^the event details must indicate "([^"]*)" was altered$`, theEventDetailsMustIndicateWasAltered)
+	ctx.Step(`^a flag with key "([^"]*)" is modified$`, aFlagWithKeyIsModified)
+	ctx.Step(`^the flag was modified$`, theFlagWasModified)
+
+	// reconnection
+	ctx.Step(`^the connection is lost$`, theConnectionIsLost)
+	ctx.Step(`^the PROVIDER_READY handler must run when the provider connects$`, theReadyHandlerMustRunWhenTheProviderConnects)
+	ctx.Step(`^the PROVIDER_ERROR handler must run when the provider's connection is lost$`, theErrorHandlerMustRunWhenTheConnectionIsLost)
+	ctx.Step(`^when the connection is reestablished the PROVIDER_READY handler must run again$`, theReadyHandlerMustRunAgainWhenTheConnectionIsReestablished)
+	ctx.Step(`^flagd is unavailable$`, flagdIsUnavailable)
+	ctx.Step(`^a flagd provider is set and initialization is awaited$`, aFlagdProviderIsSetAndInitializationIsAwaited)
+	ctx.Step(`^an error should be indicated within the configured deadline$`, anErrorShouldBeIndicatedWithinTheConfiguredDeadline)
+
+	// zero values
+	ctx.Step(`^a zero-value boolean flag with key "([^"]*)" is evaluated with default value "([^"]*)"$`, aBooleanFlagWithKeyIsEvaluatedWithDefaultValue)
+	ctx.Step(`^the resolved boolean zero-value should be "([^"]*)"$`, theResolvedBooleanValueShouldBe)
+	ctx.Step(`^a zero-value string flag with key "([^"]*)" is evaluated with default value "([^"]*)"$`, aStringFlagWithKeyIsEvaluatedWithDefaultValue)
+	ctx.Step(`^the resolved string zero-value should be "([^"]*)"$`, theResolvedStringValueShouldBe)
+	ctx.Step(`^a zero-value integer flag with key "([^"]*)" is evaluated with default value (\d+)$`, anIntegerFlagWithKeyIsEvaluatedWithDefaultValue)
+	ctx.Step(`^the resolved integer zero-value should be (\d+)$`, theResolvedIntegerValueShouldBe)
+	ctx.Step(`^a zero-value float flag with key "([^"]*)" is evaluated with default value (\-*\d+\.\d+)$`, aFloatFlagWithKeyIsEvaluatedWithDefaultValue)
+	ctx.Step(`^the resolved float zero-value should be (\-*\d+\.\d+)$`, theResolvedFloatValueShouldBe)
+
+	// caching
+	ctx.Step(`^a (boolean|string) flag with key "([^"]*)" is evaluated with details and default value "([^"]*)"$`, aFlagWithKeyIsEvaluatedWithDetailsAndDefaultValue)
+	ctx.Step(`^an? (integer|float) flag with key "([^"]*)" is evaluated with details and default value (\-*\d+(?:\.\d+)?)$`, aFlagWithKeyIsEvaluatedWithDetailsAndDefaultValue)
+	ctx.Step(`^an object flag with key "([^"]*)" is evaluated with details and a null default value$`, anObjectFlagWithKeyIsEvaluatedWithDetails)
+	ctx.Step(`^an? (boolean|string|integer|float|object) flag with key "([^"]*)" is evaluated with details$`, aFlagWithKeyIsEvaluatedWithDetails)
+	ctx.Step(`^the resolved (boolean|string) details value should be "([^"]*)", the variant should be "([^"]*)", and the reason should be "([^"]*)"$`, theResolvedDetailsValueShouldBeTheVariantShouldBeAndTheReasonShouldBe)
+	ctx.Step(`^the resolved (integer|float) details value should be (\-*\d+(?:\.\d+)?), the variant should be "([^"]*)", and the reason should be "([^"]*)"$`, theResolvedDetailsValueShouldBeTheVariantShouldBeAndTheReasonShouldBe)
+	ctx.Step(`^the resolved object details value should be contain fields "([^"]*)", "([^"]*)", and "([^"]*)", with values "([^"]*)", "([^"]*)" and (\d+), respectively$`, theResolvedObjectDetailsValueShouldContainFields)
+	ctx.Step(`^the variant should be "([^"]*)", and the reason should be "([^"]*)"$`, theEvaluatedVariantShouldBeAndTheReasonShouldBe)
+	ctx.Step(`^the (?:returned )?reason should be "([^"]*)"$`, theReasonShouldBe)
+
+	ctx.After(removeHandlers)
+}
+
+// events
+
+func aHandlerIsAdded(ctx context.Context, eventType string) (context.Context, error) {
+	ctx, handlers, err := handlersOf(ctx)
+	if err != nil {
+		return ctx, err
+	}
+
+	handlers.add(openfeature.EventType(eventType))
+
+	return ctx, nil
+}
+
+func aReadyHandlerAndAnErrorHandlerAreAdded(ctx context.Context) (context.Context, error) {
+	ctx, handlers, err := handlersOf(ctx)
+	if err != nil {
+		return ctx, err
+	}
+
+	handlers.add(openfeature.ProviderReady)
+	handlers.add(openfeature.ProviderError)
+
+	return ctx, nil
+}
+
+func theHandlerMustRun(ctx context.Context, eventType string) error {
+	handlers, ok := ctx.Value(ctxHandlersKey{}).(*eventHandlers)
+	if !ok {
+		return errors.New("no handlers added")
+	}
+
+	return handlers.await(openfeature.EventType(eventType))
+}
+
+func theEventDetailsMustIndicateWasAltered(ctx context.Context, flagKey string) error {
+	handlers, ok := ctx.Value(ctxHandlersKey{}).(*eventHandlers)
+	if !ok {
+		return errors.New("no handlers added")
+	}
+
+	details, ok := handlers.lastDetails(openfeature.ProviderConfigChange)
+	if !ok {
+		return fmt.Errorf("no %s event received", openfeature.ProviderConfigChange)
+	}
+
+	for _, changed := range details.FlagChanges {
+		if changed == flagKey {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("expected flag %s to be altered, got changes of %v", flagKey, details.FlagChanges)
+}
+
+func aFlagWithKeyIsModified(ctx context.Context, flagKey string) error {
+	if test_config.modifyFlag == nil {
+		// the flag is modified by flagd
+		return nil
+	}
+
+	return test_config.modifyFlag(flagKey)
+}
+
+func theFlagWasModified(ctx context.Context) error {
+	evaluation, ok := ctx.Value(ctxFlagEvaluationKey{}).(*flagEvaluation)
+	if !ok {
+		return errors.New("no flag evaluated")
+	}
+
+	return aFlagWithKeyIsModified(ctx, evaluation.flagKey)
+}
+
+// reconnection
+
+func theConnectionIsLost(ctx context.Context) error {
+	if test_config.loseConnection == nil {
+		// the connection is lost by flagd
+		return nil
+	}
+
+	return test_config.loseConnection()
+}
+
+func theReadyHandlerMustRunWhenTheProviderConnects(ctx context.Context) error {
+	return theHandlerMustRun(ctx, string(openfeature.ProviderReady))
+}
+
+func theErrorHandlerMustRunWhenTheConnectionIsLost(ctx context.Context) error {
+	if err := theConnectionIsLost(ctx); err != nil {
+		return err
+	}
+
+	return theHandlerMustRun(ctx, string(openfeature.ProviderError))
+}
+
+func theReadyHandlerMustRunAgainWhenTheConnectionIsReestablished(ctx context.Context) error {
+	return theHandlerMustRun(ctx, string(openfeature.ProviderReady))
+}
+
+func flagdIsUnavailable(ctx context.Context) error {
+	if test_config.unavailableProviderSupplier == nil {
+		return errors.New("no supplier of a provider for an unavailable flagd, see WithUnavailableProviderSupplier")
+	}
+
+	return nil
+}
+
+func aFlagdProviderIsSetAndInitializationIsAwaited(ctx context.Context) (context.Context, error) {
+	client := openfeature.NewClient(domain)
+	ctx = context.WithValue(ctx, ctxClientKey{}, client)
+
+	ctx, handlers, err := handlersOf(ctx)
+	if err != nil {
+		return ctx, err
+	}
+	handlers.add(openfeature.ProviderError)
+
+	if err := openfeature.SetNamedProvider(domain, test_config.unavailableProviderSupplier()); err != nil {
+		return ctx, err
+	}
+
+	return ctx, nil
+}
+
+func anErrorShouldBeIndicatedWithinTheConfiguredDeadline(ctx context.Context) error {
+	return theHandlerMustRun(ctx, string(openfeature.ProviderError))
+}
+
+// caching
+
+func aFlagWithKeyIsEvaluatedWithDetailsAndDefaultValue(
+	ctx context.Context, flagType, flagKey, defaultValueStr string,
+) (context.Context, error) {
+	client, ok := ctx.Value(ctxClientKey{}).(*openfeature.Client)
+	if !ok {
+		return ctx, errors.New("no provider set")
+	}
+
+	var evaluate func(ctx context.Context) (openfeature.InterfaceEvaluationDetails, error)
+	switch flagType {
+	case "boolean":
+		defaultValue, err := strconv.ParseBool(defaultValueStr)
+		if err != nil {
+			return ctx, errors.New("default value must be of type bool")
+		}
+		evaluate = func(ctx context.Context) (openfeature.InterfaceEvaluationDetails, error) {
+			details, err := client.BooleanValueDetails(ctx, flagKey, defaultValue, openfeature.EvaluationContext{})
+			return openfeature.InterfaceEvaluationDetails{Value: details.Value, EvaluationDetails: details.EvaluationDetails}, err
+		}
+	case "string":
+		evaluate = func(ctx context.Context) (openfeature.InterfaceEvaluationDetails, error) {
+			details, err := client.StringValueDetails(ctx, flagKey, defaultValueStr, openfeature.EvaluationContext{})
+			return openfeature.InterfaceEvaluationDetails{Value: details.Value, EvaluationDetails: details.EvaluationDetails}, err
+		}
+	case "integer":
+		defaultValue, err := strconv.ParseInt(defaultValueStr, 10, 64)
+		if err != nil {
+			return ctx, errors.New("default value must be of type int")
+		}
+		evaluate = func(ctx context.Context) (openfeature.InterfaceEvaluationDetails, error) {
+			details, err := client.IntValueDetails(ctx, flagKey, defaultValue, openfeature.EvaluationContext{})
+			return openfeature.InterfaceEvaluationDetails{Value: details.Value, EvaluationDetails: details.EvaluationDetails}, err
+		}
+	case "float":
+		defaultValue, err := strconv.ParseFloat(defaultValueStr, 64)
+		if err != nil {
+			return ctx, errors.New("default value must be of type float")
+		}
+		evaluate = func(ctx context.Context) (openfeature.InterfaceEvaluationDetails, error) {
+			details, err := client.FloatValueDetails(ctx, flagKey, defaultValue, openfeature.EvaluationContext{})
+			return openfeature.InterfaceEvaluationDetails{Value: details.Value, EvaluationDetails: details.EvaluationDetails}, err
+		}
+	case "object":
+		evaluate = func(ctx context.Context) (openfeature.InterfaceEvaluationDetails, error) {
+			return client.ObjectValueDetails(ctx, flagKey, nil, openfeature.EvaluationContext{})
+		}
+	default:
+		return ctx, fmt.Errorf("unsupported flag type %s", flagType)
+	}
+
+	evaluation := &flagEvaluation{flagKey: flagKey, evaluate: evaluate}
+
+	var err error
+	evaluation.details, err = evaluate(ctx)
+	if err != nil {
+		return ctx, fmt.Errorf("openfeature client: %w", err)
+	}
+
+	return context.WithValue(ctx, ctxFlagEvaluationKey{}, evaluation), nil
+}
+
+func aFlagWithKeyIsEvaluatedWithDetails(ctx context.Context, flagType, flagKey string) (context.Context, error) {
+	zeroValues := map[string]string{"boolean": "false", "string": "", "integer": "0", "float": "0", "object": ""}
+
+	return aFlagWithKeyIsEvaluatedWithDetailsAndDefaultValue(ctx, flagType, flagKey, zeroValues[flagType])
+}
+
+func anObjectFlagWithKeyIsEvaluatedWithDetails(ctx context.Context, flagKey string) (context.Context, error) {
+	return aFlagWithKeyIsEvaluatedWithDetailsAndDefaultValue(ctx, "object", flagKey, "")
+}
+
+func theResolvedDetailsValueShouldBeTheVariantShouldBeAndTheReasonShouldBe(
+	ctx context.Context, flagType, valueStr, variant, reason string,
+) error {
+	evaluation, ok := ctx.Value(ctxFlagEvaluationKey{}).(*flagEvaluation)
+	if !ok {
+		return errors.New("no flag evaluated")
+	}
+
+	got, err := evaluation.next(ctx)
+	if err != nil {
+		return err
+	}
+
+	if gotStr := fmt.Sprint(got.Value); !valuesEqual(flagType, gotStr, valueStr) {
+		return fmt.Errorf("expected value to be %s, got %s", valueStr, gotStr)
+	}
+
+	return checkVariantAndReason(got, variant, reason)
+}
+
+func theResolvedObjectDetailsValueShouldContainFields(
+	ctx context.Context, field1, field2, field3, value1, value2 string, value3 int,
+) error {
+	evaluation, ok := ctx.Value(ctxFlagEvaluationKey{}).(*flagEvaluation)
+	if !ok {
+		return errors.New("no flag evaluated")
+	}
+
+	got, err := evaluation.next(ctx)
+	if err != nil {
+		return err
+	}
+
+	value, ok := got.Value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected object detail value to be of type map[string]interface{}, got type: %T", got.Value)
+	}
+
+	if err := compareValueToPotentialBool(value[field1], value1); err != nil {
+		return fmt.Errorf("field '%s': %w", field1, err)
+	}
+	if err := compareValueToPotentialBool(value[field2], value2); err != nil {
+		return fmt.Errorf("field '%s': %w", field2, err)
+	}
+	if fmt.Sprint(value[field3]) != strconv.Itoa(value3) {
+		return fmt.Errorf("field '%s': expected %d, got %v", field3, value3, value[field3])
+	}
+
+	return nil
+}
+
+func theEvaluatedVariantShouldBeAndTheReasonShouldBe(ctx context.Context, variant, reason string) error {
+	evaluation, ok := ctx.Value(ctxFlagEvaluationKey{}).(*flagEvaluation)
+	if !ok {
+		return errors.New("no flag evaluated")
+	}
+
+	return checkVariantAndReason(evaluation.details, variant, reason)
+}
+
+// theReasonShouldBe evaluates the flag again, as the reason changes with caching
+func theReasonShouldBe(ctx context.Context, reason string) error {
+	evaluation, ok := ctx.Value(ctxFlagEvaluationKey{}).(*flagEvaluation)
+	if !ok {
+		return errors.New("no flag evaluated")
+	}
+
+	got, err := evaluation.evaluate(ctx)
+	if err != nil {
+		return fmt.Errorf("openfeature client: %w", err)
+	}
+	evaluation.details, evaluation.checked = got, true
+
+	if string(got.Reason) != reason {
+		return fmt.Errorf("expected reason to be %s, got %s", reason, got.Reason)
+	}
+
+	return nil
+}
+
+func checkVariantAndReason(got openfeature.InterfaceEvaluationDetails, variant, reason string) error {
+	if got.Variant != variant {
+		return fmt.Errorf("expected variant to be %s, got %s", variant, got.Variant)
+	}
+	if string(got.Reason) != reason {
+		return fmt.Errorf("expected reason to be %s, got %s", reason, got.Reason)
+	}
+
+	return nil
+}
+
+func valuesEqual(flagType, got, expected string) bool {
+	if flagType != "float" {
+		return got == expected
+	}
+
+	gotFloat, err := strconv.ParseFloat(got, 64)
+	if err != nil {
+		return false
+	}
+	expectedFloat, err := strconv.ParseFloat(expected, 64)
+	if err != nil {
+		return false
+	}
+
+	return gotFloat == expectedFloat
+}
+
+// next returns the details of the evaluation, evaluating the flag again if the details were checked before
+func (e *flagEvaluation) next(ctx context.Context) (openfeature.InterfaceEvaluationDetails, error) {
+	if e.checked {
+		details, err := e.evaluate(ctx)
+		if err != nil {
+			return details, fmt.Errorf("openfeature client: %w", err)
+		}
+		e.details = details
+	}
+	e.checked = true
+
+	return e.details, nil
+}
+
+// handlers
+
+// handlersOf returns the event handlers of the scenario, creating them with the first handler added
+func handlersOf(ctx context.Context) (context.Context, *eventHandlers, error) {
+	if handlers, ok := ctx.Value(ctxHandlersKey{}).(*eventHandlers); ok {
+		return ctx, handlers, nil
+	}
+
+	client, ok := ctx.Value(ctxClientKey{}).(*openfeature.Client)
+	if !ok {
+		return ctx, nil, errors.New("no provider set")
+	}
+
+	handlers := &eventHandlers{
+		client:    client,
+		callbacks: map[openfeature.EventType]openfeature.EventCallback{},
+		received:  map[openfeature.EventType]chan openfeature.EventDetails{},
+		last:      map[openfeature.EventType]openfeature.EventDetails{},
+	}
+
+	return context.WithValue(ctx, ctxHandlersKey{}, handlers), handlers, nil
+}
+
+func (h *eventHandlers) add(eventType openfeature.EventType) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	if _, ok := h.callbacks[eventType]; ok {
+		return
+	}
+
+	received := make(chan openfeature.EventDetails, 100)
+	callback := func(details openfeature.EventDetails) {
+		select {
+		case received <- details:
+		default:
+			// events exceeding the buffer are not awaited by any scenario
+		}
+	}
+
+	h.received[eventType] = received
+	h.callbacks[eventType] = &callback
+	h.client.AddHandler(eventType, &callback)
+}
+
+// await waits for the next event of the type received by its handler
+func (h *eventHandlers) await(eventType openfeature.EventType) error {
+	h.mtx.Lock()
+	received, ok := h.received[eventType]
+	h.mtx.Unlock()
+
+	if !ok {
+		return fmt.Errorf("no %s handler added", eventType)
+	}
+
+	select {
+	case details := <-received:
+		h.mtx.Lock()
+		h.last[eventType] = details
+		h.mtx.Unlock()
+		return nil
+	case <-time.After(test_config.eventTimeout):
+		return fmt.Errorf("%s handler did not run within %s", eventType, test_config.eventTimeout)
+	}
+}
+
+func (h *eventHandlers) lastDetails(eventType openfeature.EventType) (openfeature.EventDetails, bool) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	details, ok := h.last[eventType]
+	return details, ok
+}
+
+func removeHandlers(ctx context.Context, _ *godog.Scenario, err error) (context.Context, error) {
+	handlers, ok := ctx.Value(ctxHandlersKey{}).(*eventHandlers)
+	if !ok {
+		return ctx, err
+	}
+
+	handlers.mtx.Lock()
+	defer handlers.mtx.Unlock()
+
+	for eventType, callback := range handlers.callbacks {
+		handlers.client.RemoveHandler(eventType, callback)
+	}
+
+	return ctx, err
+}
@@ -22,8 +22,9 @@ type ctxEvaluationCtxKey struct{}
 type ctxReasonKey struct{}
 
 // InitializeFlagdJsonTestSuite register provider supplier and register test steps
-func InitializeFlagdJsonTestSuite(providerSupplier func() openfeature.FeatureProvider) func(*godog.TestSuiteContext) {
+func InitializeFlagdJsonTestSuite(providerSupplier func() openfeature.FeatureProvider, opts ...Option) func(*godog.TestSuiteContext) {
 	test_provider_supplier = providerSupplier
+	test_config = newConfig(opts...)
 
 	return func(suiteContext *godog.TestSuiteContext) {}
 }
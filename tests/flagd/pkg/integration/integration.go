@@ -2,21 +2,95 @@ package integration
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/open-feature/go-sdk/openfeature"
 )
 
+const (
+	defaultReadyTimeout = 500 * time.Millisecond
+	defaultEventTimeout = 30 * time.Second
+)
+
 var test_provider_supplier func() openfeature.FeatureProvider
 
+// test_config holds the options of the test suite
+var test_config = newConfig()
+
 // ctxStorageKey is the key used to pass test data across context.Context
 type ctxStorageKey struct{}
 
 // ctxClientKey is the key used to pass the openfeature client across context.Context
 type ctxClientKey struct{}
+
 var domain = "flagd-e2e-tests"
 
+// Option configures a test suite
+type Option func(*config)
+
+type config struct {
+	readyTimeout time.Duration
+	eventTimeout time.Duration
+
+	modifyFlag                  func(flagKey string) error
+	loseConnection              func() error
+	unavailableProviderSupplier func() openfeature.FeatureProvider
+}
+
+func newConfig(opts ...Option) config {
+	cfg := config{
+		readyTimeout: defaultReadyTimeout,
+		eventTimeout: defaultEventTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// WithReadyTimeout sets the time to wait for the provider to become ready. Defaults to 500 milliseconds, which may be
+// too short for providers fetching the flag configuration first, such as the in-process resolver
+func WithReadyTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.readyTimeout = timeout
+	}
+}
+
+// WithEventTimeout sets the time to wait for events of flag changes and reconnections. Defaults to 30 seconds
+func WithEventTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.eventTimeout = timeout
+	}
+}
+
+// WithFlagModifier sets the function modifying a flag in flagd. Without it, the flag is expected to be modified by
+// flagd, as done by the flagd testbed periodically
+func WithFlagModifier(modifyFlag func(flagKey string) error) Option {
+	return func(c *config) {
+		c.modifyFlag = modifyFlag
+	}
+}
+
+// WithConnectionLoss sets the function making the provider lose its connection to flagd, which is expected to be
+// reestablished later on. Without it, the connection is expected to be lost by flagd, as done by the flagd testbed
+// periodically
+func WithConnectionLoss(loseConnection func() error) Option {
+	return func(c *config) {
+		c.loseConnection = loseConnection
+	}
+}
+
+// WithUnavailableProviderSupplier sets the supplier of a provider configured for a flagd which is unavailable
+func WithUnavailableProviderSupplier(providerSupplier func() openfeature.FeatureProvider) Option {
+	return func(c *config) {
+		c.unavailableProviderSupplier = providerSupplier
+	}
+}
+
 func aFlagdProviderIsSet(ctx context.Context) (context.Context, error) {
 	readyChan := make(chan struct{})
 
@@ -25,18 +99,21 @@ func aFlagdProviderIsSet(ctx context.Context) (context.Context, error) {
 		return nil, err
 	}
 
+	// the handler runs again once the provider reconnects
+	var once sync.Once
 	callBack := func(details openfeature.EventDetails) {
 		// emit readiness
-		close(readyChan)
+		once.Do(func() { close(readyChan) })
 	}
 
 	client := openfeature.NewClient(domain)
 	client.AddHandler(openfeature.ProviderReady, &callBack)
+	defer client.RemoveHandler(openfeature.ProviderReady, &callBack)
 
 	select {
 	case <-readyChan:
-	case <-time.After(500 * time.Millisecond):
-		return ctx, errors.New("provider not ready after 500 milliseconds")
+	case <-time.After(test_config.readyTimeout):
+		return ctx, fmt.Errorf("provider not ready after %s", test_config.readyTimeout)
 	}
 
 	return context.WithValue(ctx, ctxClientKey{}, client), nil